@@ -1,11 +1,21 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 
 	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/config"
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/handler"
 	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/repository"
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/router"
 	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/service"
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/worker"
 	"github.com/joho/godotenv"
 )
 
@@ -21,10 +31,104 @@ func main() {
 	// Initialize the repositories
 	bookRepo := repository.NewBookRepository(db)
 	categoryRepo := repository.NewCategoryRepository(db)
+	publisherRepo := repository.NewPublisherRepository(db)
+	seriesRepo := repository.NewSeriesRepository(db)
 	authorRepo := repository.NewAuthorRepository(db)
+	auditLogRepo := repository.NewAuditLogRepository(db)
+
+	// Initialize the unit of work used by the writes, so the changes
+	// and their audit log entries are committed together
+	uow := repository.NewUnitOfWork(db)
 
 	// Initialize the services
-	bookService := service.NewBookService(bookRepo, categoryRepo, authorRepo)
-	categoryService := service.NewCategoryService(categoryRepo)
-	authorService := service.NewAuthorService(authorRepo)
+	bookService := service.NewBookService(bookRepo, uow)
+	categoryService := service.NewCategoryService(categoryRepo, uow)
+	publisherService := service.NewPublisherService(publisherRepo, uow)
+	seriesService := service.NewSeriesService(seriesRepo, uow)
+	authorService := service.NewAuthorService(authorRepo, uow)
+	auditService := service.NewAuditService(auditLogRepo)
+
+	// Initialize the handlers
+	authorHandler := handler.NewAuthorHandler(authorService)
+	categoryHandler := handler.NewCategoryHandler(categoryService)
+	bookHandler := handler.NewBookHandler(bookService)
+	publisherHandler := handler.NewPublisherHandler(publisherService)
+	seriesHandler := handler.NewSeriesHandler(seriesService)
+	trashHandler := handler.NewTrashHandler(bookHandler, authorHandler, categoryHandler, publisherHandler, seriesHandler)
+	auditHandler := handler.NewAuditHandler(auditService)
+
+	// Initialize the router
+	r := router.New(router.Handlers{
+		Author:    authorHandler,
+		Category:  categoryHandler,
+		Book:      bookHandler,
+		Publisher: publisherHandler,
+		Series:    seriesHandler,
+		Trash:     trashHandler,
+		Audit:     auditHandler,
+	}, router.Options{
+		QueryTimeout: config.QueryTimeout(),
+		AdminToken:   config.AdminToken(),
+	})
+
+	server := &http.Server{
+		Addr:    config.ServerAddress(),
+		Handler: r,
+	}
+
+	// Cancelled on an interrupt or termination signal
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Start the background purge of the deleted records
+	var purgerDone sync.WaitGroup
+	if config.PurgeEnabled() {
+		purgeService := service.NewPurgeService(
+			repository.NewRepositories(db),
+			config.PurgeRetention(),
+			config.PurgeBatchSize(),
+		)
+		purger := worker.NewPurger(purgeService, config.PurgeInterval())
+
+		purgerDone.Add(1)
+		go func() {
+			defer purgerDone.Done()
+			purger.Run(ctx)
+		}()
+	}
+
+	go func() {
+		log.Printf("Server listening on %s", server.Addr)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Error while starting the server: %v", err)
+		}
+	}()
+
+	// Wait for an interrupt or termination signal
+	<-ctx.Done()
+
+	log.Println("Shutting down the server...")
+
+	// Give in-flight requests some time to finish
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), config.ShutdownTimeout())
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Error while shutting down the server: %v", err)
+	}
+
+	// Wait for a purge in progress to stop
+	purgerDone.Wait()
+
+	// Close the database connection pool
+	sqlDB, err := db.DB()
+	if err != nil {
+		log.Printf("Error while getting the database connection pool: %v", err)
+		return
+	}
+	if err := sqlDB.Close(); err != nil {
+		log.Printf("Error while closing the database connection pool: %v", err)
+	}
+
+	log.Println("Server stopped")
 }
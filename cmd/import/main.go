@@ -0,0 +1,76 @@
+// Command import loads books from a CSV file into the catalogue, see the
+// bookcsv package for the expected columns
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/config"
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/repository"
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/service"
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	file := flag.String("file", "-", "CSV file to import, \"-\" reads from the standard input")
+	dryRun := flag.Bool("dry-run", false, "validate the rows and report the result without saving anything")
+	actor := flag.String("actor", "import", "actor recorded on the audit log")
+	flag.Parse()
+
+	err := godotenv.Load(".env")
+	if err != nil {
+		log.Fatal("Error loading .env file")
+	}
+
+	var input io.Reader = os.Stdin
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			log.Fatalf("Error opening the CSV file: %v", err)
+		}
+		defer f.Close()
+
+		input = f
+	}
+
+	// Initialize the database connection
+	db := config.Database()
+
+	bookService := service.NewBookService(repository.NewBookRepository(db), repository.NewUnitOfWork(db))
+
+	// Cancelled on an interrupt or termination signal, rolling back the import
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	report, err := bookService.ImportBooks(service.WithActor(ctx, *actor), input, *dryRun)
+	if err != nil {
+		log.Fatalf("Error importing the books: %v", err)
+	}
+
+	for _, result := range report.Results {
+		if result.Err != nil {
+			fmt.Printf("line %d: failed: %v\n", result.Line, result.Err)
+			continue
+		}
+
+		fmt.Printf("line %d: imported %q (%s)\n", result.Line, result.Book.Title, result.Book.ID)
+	}
+
+	summary := fmt.Sprintf("%d imported, %d failed", report.Imported, report.Failed)
+	if report.DryRun {
+		summary += " (dry run, nothing was saved)"
+	}
+	fmt.Println(summary)
+
+	if report.Failed > 0 {
+		stop()
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,50 @@
+// Package bookcsv reads and writes books as CSV, one book per row with
+// the authors and categories names in a single cell separated by ";"
+package bookcsv
+
+import (
+	"strings"
+)
+
+const (
+	ColumnTitle          = "title"
+	ColumnSynopsis       = "synopsis"
+	ColumnISBN           = "isbn"
+	ColumnPublisher      = "publisher"
+	ColumnSeries         = "series"
+	ColumnSeriesPosition = "series_position"
+	ColumnAuthors        = "authors"
+	ColumnCategories     = "categories"
+)
+
+// Header is the header written on the exports and expected on the imports
+var Header = []string{
+	ColumnTitle,
+	ColumnSynopsis,
+	ColumnISBN,
+	ColumnPublisher,
+	ColumnSeries,
+	ColumnSeriesPosition,
+	ColumnAuthors,
+	ColumnCategories,
+}
+
+// ValueSeparator separates the names inside the authors and categories cells
+const ValueSeparator = ";"
+
+// splitValues returns the trimmed, non empty values of a multi-value cell
+func splitValues(cell string) []string {
+	var values []string
+	for _, value := range strings.Split(cell, ValueSeparator) {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
+
+// joinValues builds a multi-value cell
+func joinValues(values []string) string {
+	return strings.Join(values, ValueSeparator+" ")
+}
@@ -0,0 +1,139 @@
+package bookcsv
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/domain"
+)
+
+// utf8BOM is written by some spreadsheet editors at the start of the file
+const utf8BOM = "\ufeff"
+
+// Record is a row read from the CSV. Err is set when the row itself is
+// malformed, the book is only checked against the CSV layout
+type Record struct {
+	Line int
+	Book *domain.Book
+	Err  error
+}
+
+type Reader struct {
+	csv     *csv.Reader
+	columns map[string]int
+}
+
+// NewReader reads the header of the CSV, the columns are matched by name
+// in any order and the unknown ones are ignored
+func NewReader(r io.Reader) (*Reader, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, domain.NewValidationError("csv", "is empty")
+	}
+	if err != nil {
+		return nil, readError(err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		if i == 0 {
+			name = strings.TrimPrefix(name, utf8BOM)
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+
+		if _, ok := columns[name]; !ok {
+			columns[name] = i
+		}
+	}
+
+	for _, required := range []string{ColumnTitle, ColumnSynopsis} {
+		if _, ok := columns[required]; !ok {
+			return nil, domain.NewValidationError("csv", fmt.Sprintf("header must have the %q column", required))
+		}
+	}
+
+	return &Reader{reader, columns}, nil
+}
+
+// Read returns the next row, or io.EOF when there are no more rows.
+// The returned error is only set when the CSV can not be read anymore
+func (r *Reader) Read() (*Record, error) {
+	row, err := r.csv.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, io.EOF
+	}
+
+	var parseError *csv.ParseError
+	if errors.As(err, &parseError) {
+		return &Record{
+			Line: parseError.StartLine,
+			Err:  domain.NewValidationError("csv", parseError.Err.Error()),
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	line, _ := r.csv.FieldPos(0)
+
+	book := &domain.Book{
+		Title:    r.cell(row, ColumnTitle),
+		Synopsis: r.cell(row, ColumnSynopsis),
+	}
+
+	if value := r.cell(row, ColumnISBN); value != "" {
+		book.ISBN = &value
+	}
+	if value := r.cell(row, ColumnPublisher); value != "" {
+		book.Publisher = &domain.Publisher{Name: value}
+	}
+	if value := r.cell(row, ColumnSeries); value != "" {
+		book.Series = &domain.Series{Name: value}
+	}
+	if value := r.cell(row, ColumnSeriesPosition); value != "" {
+		position, err := strconv.Atoi(value)
+		if err != nil {
+			return &Record{
+				Line: line,
+				Err:  domain.NewValidationError(ColumnSeriesPosition, "must be a number"),
+			}, nil
+		}
+		book.SeriesPosition = &position
+	}
+
+	for _, name := range splitValues(r.cell(row, ColumnAuthors)) {
+		book.Authors = append(book.Authors, domain.Author{Name: name})
+	}
+	for _, name := range splitValues(r.cell(row, ColumnCategories)) {
+		book.Categories = append(book.Categories, domain.Category{Name: name})
+	}
+
+	return &Record{Line: line, Book: book}, nil
+}
+
+// cell returns the trimmed value of the column, missing columns are empty
+func (r *Reader) cell(row []string, column string) string {
+	i, ok := r.columns[column]
+	if !ok || i >= len(row) {
+		return ""
+	}
+
+	return strings.TrimSpace(row[i])
+}
+
+func readError(err error) error {
+	var parseError *csv.ParseError
+	if errors.As(err, &parseError) {
+		return domain.NewValidationError("csv", parseError.Error())
+	}
+
+	return err
+}
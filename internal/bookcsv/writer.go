@@ -0,0 +1,84 @@
+package bookcsv
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/domain"
+)
+
+// Writer writes books using the same layout read by Reader,
+// so an export can be imported back
+type Writer struct {
+	csv           *csv.Writer
+	headerWritten bool
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{csv: csv.NewWriter(w)}
+}
+
+// Write writes the book as a row, the header is written before the first one
+func (w *Writer) Write(book *domain.Book) error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+
+	// The authors are written as credited, the aliases resolve
+	// back to the same authors when the file is imported
+	authors := make([]string, 0, len(book.Authors))
+	for _, author := range book.Authors {
+		authors = append(authors, book.CreditedName(author))
+	}
+
+	categories := make([]string, 0, len(book.Categories))
+	for _, category := range book.Categories {
+		categories = append(categories, category.Name)
+	}
+
+	var isbn, publisher, series, seriesPosition string
+	if book.ISBN != nil {
+		isbn = *book.ISBN
+	}
+	if book.Publisher != nil {
+		publisher = book.Publisher.Name
+	}
+	if book.Series != nil && book.SeriesPosition != nil {
+		series = book.Series.Name
+		seriesPosition = strconv.Itoa(*book.SeriesPosition)
+	}
+
+	return w.csv.Write([]string{
+		book.Title,
+		book.Synopsis,
+		isbn,
+		publisher,
+		series,
+		seriesPosition,
+		joinValues(authors),
+		joinValues(categories),
+	})
+}
+
+// Flush writes the buffered rows, the header is written even if there were
+// no books
+func (w *Writer) Flush() error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+
+	w.csv.Flush()
+
+	return w.csv.Error()
+}
+
+func (w *Writer) writeHeader() error {
+	if w.headerWritten {
+		return nil
+	}
+
+	w.headerWritten = true
+
+	return w.csv.Write(Header)
+}
@@ -0,0 +1,9 @@
+package config
+
+import "os"
+
+// AdminToken returns the token the administrators send to access
+// the restricted endpoints, they are disabled when it is empty
+func AdminToken() string {
+	return os.Getenv("ADMIN_TOKEN")
+}
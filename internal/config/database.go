@@ -21,7 +21,10 @@ func Database() *gorm.DB {
 		host, user, password, dbname, port,
 	)
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		// Expose the unique and foreign key violations as gorm errors
+		TranslateError: true,
+	})
 	if err != nil {
 		panic("Failed to connect to database: " + err.Error())
 	}
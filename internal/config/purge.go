@@ -0,0 +1,40 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultPurgeRetention = 30 * 24 * time.Hour
+	defaultPurgeInterval  = 24 * time.Hour
+	defaultPurgeBatchSize = 500
+)
+
+// PurgeEnabled tells if the background purge of the deleted records runs,
+// it is enabled unless PURGE_ENABLED is set to false
+func PurgeEnabled() bool {
+	enabled, err := strconv.ParseBool(os.Getenv("PURGE_ENABLED"))
+	return err != nil || enabled
+}
+
+// PurgeRetention returns how long the deleted records are kept before being purged
+func PurgeRetention() time.Duration {
+	return durationFromEnv("PURGE_RETENTION", defaultPurgeRetention)
+}
+
+// PurgeInterval returns how often the purge runs
+func PurgeInterval() time.Duration {
+	return durationFromEnv("PURGE_INTERVAL", defaultPurgeInterval)
+}
+
+// PurgeBatchSize returns how many records are purged on each transaction
+func PurgeBatchSize() int {
+	size, err := strconv.Atoi(os.Getenv("PURGE_BATCH_SIZE"))
+	if err != nil || size <= 0 {
+		return defaultPurgeBatchSize
+	}
+
+	return size
+}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"os"
+	"time"
+)
+
+const (
+	defaultServerAddress   = ":8080"
+	defaultShutdownTimeout = 10 * time.Second
+	defaultQueryTimeout    = 5 * time.Second
+)
+
+// ServerAddress returns the address the HTTP server listens on
+func ServerAddress() string {
+	if address := os.Getenv("API_ADDR"); address != "" {
+		return address
+	}
+
+	return defaultServerAddress
+}
+
+// ShutdownTimeout returns how long the server waits for in-flight
+// requests to finish before forcing the shutdown
+func ShutdownTimeout() time.Duration {
+	return durationFromEnv("API_SHUTDOWN_TIMEOUT", defaultShutdownTimeout)
+}
+
+// QueryTimeout returns the deadline given to the database
+// queries started while handling a request
+func QueryTimeout() time.Duration {
+	return durationFromEnv("DB_QUERY_TIMEOUT", defaultQueryTimeout)
+}
+
+func durationFromEnv(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		return fallback
+	}
+
+	return duration
+}
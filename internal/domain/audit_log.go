@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type AuditAction string
+
+const (
+	AuditActionCreate  AuditAction = "create"
+	AuditActionUpdate  AuditAction = "update"
+	AuditActionDelete  AuditAction = "delete"
+	AuditActionRestore AuditAction = "restore"
+	AuditActionPurge   AuditAction = "purge"
+	AuditActionMerge   AuditAction = "merge"
+)
+
+// AuditLog records a change made to a book, author or category.
+// Actor is verified by the API, while ClaimedActor is the name sent
+// by the client on the X-Actor header, which nothing verifies
+type AuditLog struct {
+	ID           string       `gorm:"type:char(36);primaryKey"`
+	EntityType   string       `gorm:"type:varchar(50);not null"`
+	EntityID     string       `gorm:"type:char(36);not null"`
+	Action       AuditAction  `gorm:"type:varchar(20);not null"`
+	Actor        string       `gorm:"type:varchar(100);not null"`
+	ClaimedActor *string      `gorm:"type:varchar(100)"`
+	Changes      AuditChanges `gorm:"type:jsonb;not null"`
+	CreatedAt    time.Time    `gorm:"autoCreateTime"`
+}
+
+// AuditChange holds the value of a field before and after a change
+type AuditChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// AuditChanges maps each changed field to its values, it is stored as JSON
+type AuditChanges map[string]AuditChange
+
+func (c AuditChanges) Value() (driver.Value, error) {
+	if c == nil {
+		return "{}", nil
+	}
+
+	raw, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(raw), nil
+}
+
+func (c *AuditChanges) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, c)
+	case string:
+		return json.Unmarshal([]byte(v), c)
+	case nil:
+		*c = nil
+		return nil
+	default:
+		return errors.New("unsupported type for audit changes")
+	}
+}
+
+func (a *AuditLog) BeforeCreate(tx *gorm.DB) error {
+	if a.ID != "" {
+		return nil
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return errors.New("failed to generate UUID: " + err.Error())
+	}
+
+	a.ID = id.String()
+
+	return nil
+}
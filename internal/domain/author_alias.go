@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AuthorAlias is another name an author publishes under,
+// such as a pen name or a heteronym
+type AuthorAlias struct {
+	ID        string    `gorm:"type:char(36);primaryKey"`
+	AuthorID  string    `gorm:"type:char(36);not null"`
+	Name      string    `gorm:"type:varchar(100);not null"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+func (a *AuthorAlias) BeforeCreate(tx *gorm.DB) error {
+	if a.ID != "" {
+		return nil
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return errors.New("failed to generate UUID: " + err.Error())
+	}
+
+	a.ID = id.String()
+
+	return nil
+}
@@ -13,9 +13,16 @@ type Base struct {
 	CreatedAt time.Time      `gorm:"autoCreateTime"`
 	UpdatedAt time.Time      `gorm:"autoUpdateTime"`
 	DeletedAt gorm.DeletedAt `gorm:"index"`
+	// Version is increased on every update and is
+	// used to detect concurrent changes to the record
+	Version int64 `gorm:"not null;default:1"`
 }
 
 func (b *Base) BeforeCreate(tx *gorm.DB) error {
+	if b.Version == 0 {
+		b.Version = 1
+	}
+
 	if b.ID != "" {
 		// If the ID is already set, don't generate a new one
 		// This is useful when we need to set the ID ourselves
@@ -2,8 +2,26 @@ package domain
 
 type Book struct {
 	Base
-	Title      string     `gorm:"type:varchar(255);not null"`
-	Synopsis   string     `gorm:"type:text;not null"`
-	Categories []Category `gorm:"many2many:book_categories;"`
-	Authors    []Author   `gorm:"many2many:book_authors;"`
+	Title          string       `gorm:"type:varchar(255);not null"`
+	Synopsis       string       `gorm:"type:text;not null"`
+	ISBN           *string      `gorm:"type:varchar(13)"` // ISBN-13 form, nil when the book has none
+	PublisherID    *string      `gorm:"type:char(36)"`
+	Publisher      *Publisher   `gorm:"foreignKey:PublisherID"`
+	SeriesID       *string      `gorm:"type:char(36)"`
+	SeriesPosition *int         // Volume number inside the series, set together with SeriesID
+	Series         *Series      `gorm:"foreignKey:SeriesID"`
+	Categories     []Category   `gorm:"many2many:book_categories;"`
+	Authors        []Author     `gorm:"many2many:book_authors;"`
+	AuthorCredits  []BookAuthor `gorm:"foreignKey:BookID"` // Rows of book_authors, written through UpdateAuthorCredits
+}
+
+// CreditedName returns the name the author is credited under on the book
+func (b *Book) CreditedName(author Author) string {
+	for _, credit := range b.AuthorCredits {
+		if credit.AuthorID == author.ID && credit.CreditedAs != nil {
+			return *credit.CreditedAs
+		}
+	}
+
+	return author.Name
 }
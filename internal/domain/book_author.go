@@ -0,0 +1,13 @@
+package domain
+
+// BookAuthor is the row of the book_authors join table, CreditedAs is
+// the alias the author is credited under on the book (nil for the name)
+type BookAuthor struct {
+	BookID     string  `gorm:"type:char(36);primaryKey"`
+	AuthorID   string  `gorm:"type:char(36);primaryKey"`
+	CreditedAs *string `gorm:"type:varchar(100)"`
+}
+
+func (BookAuthor) TableName() string {
+	return "book_authors"
+}
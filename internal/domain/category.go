@@ -2,5 +2,6 @@ package domain
 
 type Category struct {
 	Base
-	Name string `gorm:"type:varchar(100);unique;not null"`
+	Name     string  `gorm:"type:varchar(100);unique;not null"`
+	ParentID *string `gorm:"type:char(36)"` // nil on the root categories
 }
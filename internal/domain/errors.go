@@ -0,0 +1,39 @@
+package domain
+
+import "errors"
+
+// Sentinel errors shared by the repositories, services and handlers.
+// They are wrapped with more context along the way, so they must be
+// checked with errors.Is
+var (
+	ErrNotFound   = errors.New("resource not found")
+	ErrConflict   = errors.New("resource conflict")
+	ErrValidation = errors.New("validation failed")
+	ErrInternal   = errors.New("internal error")
+	// ErrPreconditionFailed is returned when the record was changed
+	// since the version the client based its change on
+	ErrPreconditionFailed = errors.New("precondition failed")
+)
+
+// ValidationError describes why a single field is invalid,
+// it matches ErrValidation when checked with errors.Is
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func NewValidationError(field, message string) error {
+	return &ValidationError{Field: field, Message: message}
+}
+
+func (e *ValidationError) Error() string {
+	if e.Field == "" {
+		return e.Message
+	}
+
+	return e.Field + " " + e.Message
+}
+
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
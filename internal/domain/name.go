@@ -0,0 +1,9 @@
+package domain
+
+import "strings"
+
+// NormalizeName trims the name and collapses its inner spaces. The case and
+// the accents are kept, the database ignores them when comparing the names
+func NormalizeName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
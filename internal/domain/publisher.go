@@ -0,0 +1,6 @@
+package domain
+
+type Publisher struct {
+	Base
+	Name string `gorm:"type:varchar(150);not null"`
+}
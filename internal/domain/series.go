@@ -0,0 +1,7 @@
+package domain
+
+// Series groups the books published as volumes of a single work
+type Series struct {
+	Base
+	Name string `gorm:"type:varchar(255);not null"`
+}
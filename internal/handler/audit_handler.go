@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/domain"
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/repository"
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type AuditHandler struct {
+	auditService service.AuditService
+}
+
+type auditChangeResponse struct {
+	Before interface{}
+	After  interface{}
+}
+
+// auditLogResponse is an audit log entry, Actor is verified by the API
+// while ClaimedActor is the unauthenticated X-Actor header of the request
+type auditLogResponse struct {
+	ID           string
+	EntityType   string
+	EntityID     string
+	Action       string
+	Actor        string
+	ClaimedActor string
+	Changes      map[string]auditChangeResponse
+	CreatedAt    time.Time
+}
+
+func NewAuditHandler(auditService service.AuditService) *AuditHandler {
+	return &AuditHandler{auditService}
+}
+
+func (h *AuditHandler) FindAuditLogs(c *gin.Context) {
+	filter, err := h.parseAuditLogFilter(c)
+	if err != nil {
+		respondProblem(
+			c,
+			http.StatusBadRequest,
+			"INVALID_REQUEST_PARAMETER",
+			"invalid request parameter",
+			"Error while parsing audit log filter: "+err.Error(),
+		)
+		return
+	}
+
+	page, err := parsePageRequest(c)
+	if err != nil {
+		respondProblem(
+			c,
+			http.StatusBadRequest,
+			"INVALID_REQUEST_PARAMETER",
+			"invalid request parameter",
+			"Error while parsing pagination: "+err.Error(),
+		)
+		return
+	}
+
+	auditLogs, pageInfo, err := h.auditService.FindAuditLogs(c.Request.Context(), filter, page)
+	if err != nil {
+		respondError(c, "FIND_AUDIT_LOGS_ERROR", "error while finding audit logs", err)
+		return
+	}
+
+	auditLogsResponse := []auditLogResponse{}
+	for _, auditLog := range auditLogs {
+		auditLogsResponse = append(auditLogsResponse, h.formatAuditLogResponse(auditLog))
+	}
+
+	c.JSON(
+		http.StatusOK,
+		gin.H{
+			"data": gin.H{
+				"audit_logs": auditLogsResponse,
+			},
+			"page": formatPageResponse(pageInfo),
+		},
+	)
+}
+
+// parseAuditLogFilter reads the "entity_type", "entity_id", "actor",
+// "claimed_actor", "from" and "to" query parameters
+func (h *AuditHandler) parseAuditLogFilter(c *gin.Context) (repository.AuditLogFilter, error) {
+	filter := repository.AuditLogFilter{
+		EntityType:   c.Query("entity_type"),
+		EntityID:     c.Query("entity_id"),
+		Actor:        c.Query("actor"),
+		ClaimedActor: c.Query("claimed_actor"),
+	}
+
+	if value := c.Query("from"); value != "" {
+		from, err := parseFilterDate(value)
+		if err != nil {
+			return filter, fmt.Errorf("from: %v", err)
+		}
+		filter.From = &from
+	}
+
+	if value := c.Query("to"); value != "" {
+		to, err := parseFilterDate(value)
+		if err != nil {
+			return filter, fmt.Errorf("to: %v", err)
+		}
+		filter.To = &to
+	}
+
+	return filter, nil
+}
+
+func (h *AuditHandler) formatAuditLogResponse(auditLog *domain.AuditLog) auditLogResponse {
+	changes := make(map[string]auditChangeResponse, len(auditLog.Changes))
+	for field, change := range auditLog.Changes {
+		changes[field] = auditChangeResponse{
+			Before: change.Before,
+			After:  change.After,
+		}
+	}
+
+	response := auditLogResponse{
+		ID:         auditLog.ID,
+		EntityType: auditLog.EntityType,
+		EntityID:   auditLog.EntityID,
+		Action:     string(auditLog.Action),
+		Actor:      auditLog.Actor,
+		Changes:    changes,
+		CreatedAt:  auditLog.CreatedAt,
+	}
+
+	if auditLog.ClaimedActor != nil {
+		response.ClaimedActor = *auditLog.ClaimedActor
+	}
+
+	return response
+}
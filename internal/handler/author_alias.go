@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+type authorAliasRequest struct {
+	Name string `binding:"required"`
+}
+
+type authorAliasResponse struct {
+	ID   string
+	Name string
+}
+
+// FindAuthorAliases lists the other names the author publishes under
+func (h *AuthorHandler) FindAuthorAliases(c *gin.Context) {
+	authorID := c.Param("id")
+	if authorID == "" {
+		respondProblem(
+			c,
+			http.StatusBadRequest,
+			"INVALID_REQUEST_PARAMETER",
+			"invalid request parameter",
+			"Author ID is required",
+		)
+		return
+	}
+
+	aliases, err := h.authorService.FindAuthorAliases(c.Request.Context(), authorID)
+	if err != nil {
+		respondError(c, "FIND_AUTHOR_ALIASES_ERROR", "error while finding author aliases", err)
+		return
+	}
+
+	aliasesResponse := []authorAliasResponse{}
+	for _, alias := range aliases {
+		aliasesResponse = append(aliasesResponse, authorAliasResponse{
+			ID:   alias.ID,
+			Name: alias.Name,
+		})
+	}
+
+	c.JSON(
+		http.StatusOK,
+		gin.H{
+			"data": gin.H{
+				"aliases": aliasesResponse,
+			},
+		},
+	)
+}
+
+func (h *AuthorHandler) AddAuthorAlias(c *gin.Context) {
+	authorID := c.Param("id")
+	if authorID == "" {
+		respondProblem(
+			c,
+			http.StatusBadRequest,
+			"INVALID_REQUEST_PARAMETER",
+			"invalid request parameter",
+			"Author ID is required",
+		)
+		return
+	}
+
+	var request authorAliasRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondBindingError(c, err)
+		return
+	}
+
+	var alias domain.AuthorAlias
+	alias.AuthorID = authorID
+	alias.Name = request.Name
+
+	if err := h.authorService.AddAuthorAlias(c.Request.Context(), &alias); err != nil {
+		respondError(c, "ADD_AUTHOR_ALIAS_ERROR", "error while adding author alias", err)
+		return
+	}
+
+	c.JSON(
+		http.StatusCreated,
+		gin.H{
+			"data": gin.H{
+				"message": "Author alias added successfully",
+			},
+		},
+	)
+}
+
+func (h *AuthorHandler) RemoveAuthorAlias(c *gin.Context) {
+	authorID := c.Param("id")
+	aliasID := c.Param("alias_id")
+	if authorID == "" || aliasID == "" {
+		respondProblem(
+			c,
+			http.StatusBadRequest,
+			"INVALID_REQUEST_PARAMETER",
+			"invalid request parameter",
+			"Author ID and alias ID are required",
+		)
+		return
+	}
+
+	if err := h.authorService.RemoveAuthorAlias(c.Request.Context(), authorID, aliasID); err != nil {
+		respondError(c, "REMOVE_AUTHOR_ALIAS_ERROR", "error while removing author alias", err)
+		return
+	}
+
+	c.JSON(
+		http.StatusNoContent,
+		gin.H{},
+	)
+}
@@ -1,7 +1,6 @@
 package handler
 
 import (
-	"errors"
 	"net/http"
 
 	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/domain"
@@ -29,33 +28,15 @@ func NewAuthorHandler(authorService service.AuthorService) *AuthorHandler {
 func (h *AuthorHandler) CreateAuthor(c *gin.Context) {
 	var request authorRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(
-			http.StatusBadRequest,
-			gin.H{
-				"error": gin.H{
-					"code":    "INVALID_REQUEST_BODY",
-					"message": "invalid request body",
-					"details": errors.New("Error while binding JSON: " + err.Error()),
-				},
-			},
-		)
+		respondBindingError(c, err)
 		return
 	}
 
 	var author domain.Author
 	author.Name = request.Name
 
-	if err := h.authorService.CreateAuthor(&author); err != nil {
-		c.JSON(
-			http.StatusBadRequest,
-			gin.H{
-				"error": gin.H{
-					"code":    "CREATE_AUTHOR_ERROR",
-					"message": "error while creating author",
-					"details": errors.New("Error while creating author: " + err.Error()),
-				},
-			},
-		)
+	if err := h.authorService.CreateAuthor(c.Request.Context(), &author); err != nil {
+		respondError(c, "CREATE_AUTHOR_ERROR", "error while creating author", err)
 		return
 	}
 
@@ -72,34 +53,24 @@ func (h *AuthorHandler) CreateAuthor(c *gin.Context) {
 func (h *AuthorHandler) FindAuthorByID(c *gin.Context) {
 	authorID := c.Param("id")
 	if authorID == "" {
-		c.JSON(
+		respondProblem(
+			c,
 			http.StatusBadRequest,
-			gin.H{
-				"error": gin.H{
-					"code":    "INVALID_REQUEST_PARAMETER",
-					"message": "invalid request parameter",
-					"details": errors.New("Author ID is required"),
-				},
-			},
+			"INVALID_REQUEST_PARAMETER",
+			"invalid request parameter",
+			"Author ID is required",
 		)
 		return
 	}
 
-	author, err := h.authorService.FindAuthorByID(authorID)
+	author, err := h.authorService.FindAuthorByID(c.Request.Context(), authorID)
 	if err != nil {
-		c.JSON(
-			http.StatusBadRequest,
-			gin.H{
-				"error": gin.H{
-					"code":    "FIND_AUTHOR_BY_ID_ERROR",
-					"message": "error while finding author by ID",
-					"details": errors.New("Error while finding author by ID: " + err.Error()),
-				},
-			},
-		)
+		respondError(c, "FIND_AUTHOR_BY_ID_ERROR", "error while finding author by ID", err)
 		return
 	}
 
+	setETag(c, author.Version)
+
 	c.JSON(
 		http.StatusOK,
 		gin.H{
@@ -113,34 +84,24 @@ func (h *AuthorHandler) FindAuthorByID(c *gin.Context) {
 func (h *AuthorHandler) FindAuthorByName(c *gin.Context) {
 	authorName := c.Param("name")
 	if authorName == "" {
-		c.JSON(
+		respondProblem(
+			c,
 			http.StatusBadRequest,
-			gin.H{
-				"error": gin.H{
-					"code":    "INVALID_REQUEST_PARAMETER",
-					"message": "invalid request parameter",
-					"details": "Author name is required",
-				},
-			},
+			"INVALID_REQUEST_PARAMETER",
+			"invalid request parameter",
+			"Author name is required",
 		)
 		return
 	}
 
-	author, err := h.authorService.FindAuthorByName(authorName)
+	author, err := h.authorService.FindAuthorByName(c.Request.Context(), authorName)
 	if err != nil {
-		c.JSON(
-			http.StatusBadRequest,
-			gin.H{
-				"error": gin.H{
-					"code":    "FIND_AUTHOR_BY_NAME_ERROR",
-					"message": "error while finding author by name",
-					"details": errors.New("Error while finding author by name: " + err.Error()),
-				},
-			},
-		)
+		respondError(c, "FIND_AUTHOR_BY_NAME_ERROR", "error while finding author by name", err)
 		return
 	}
 
+	setETag(c, author.Version)
+
 	c.JSON(
 		http.StatusOK,
 		gin.H{
@@ -152,36 +113,25 @@ func (h *AuthorHandler) FindAuthorByName(c *gin.Context) {
 }
 
 func (h *AuthorHandler) FindAllAuthors(c *gin.Context) {
-	authors, err := h.authorService.FindAllAuthors()
+	page, err := parsePageRequest(c)
 	if err != nil {
-		c.JSON(
+		respondProblem(
+			c,
 			http.StatusBadRequest,
-			gin.H{
-				"error": gin.H{
-					"code":    "FIND_ALL_AUTHORS_ERROR",
-					"message": "error while finding all authors",
-					"details": errors.New("Error while finding all authors: " + err.Error()),
-				},
-			},
+			"INVALID_REQUEST_PARAMETER",
+			"invalid request parameter",
+			"Error while parsing pagination: "+err.Error(),
 		)
 		return
 	}
 
-	if len(authors) == 0 {
-		c.JSON(
-			http.StatusNotFound,
-			gin.H{
-				"error": gin.H{
-					"code":    "AUTHORS_NOT_FOUND",
-					"message": "authors not found",
-					"details": "Authors not found in the database",
-				},
-			},
-		)
+	authors, pageInfo, err := h.authorService.FindAllAuthors(c.Request.Context(), page)
+	if err != nil {
+		respondError(c, "FIND_ALL_AUTHORS_ERROR", "error while finding all authors", err)
 		return
 	}
 
-	var authorsResponse []*authorResponse
+	authorsResponse := []*authorResponse{}
 	for _, author := range authors {
 		authorsResponse = append(authorsResponse, h.formatAuthorResponse(author))
 	}
@@ -192,40 +142,80 @@ func (h *AuthorHandler) FindAllAuthors(c *gin.Context) {
 			"data": gin.H{
 				"authors": authorsResponse,
 			},
+			"page": formatPageResponse(pageInfo),
 		},
 	)
 }
 
 func (h *AuthorHandler) UpdateAuthor(c *gin.Context) {
-	var author domain.Author
-	if err := c.ShouldBindJSON(&author); err != nil {
-		c.JSON(
+	authorID := c.Param("id")
+	if authorID == "" {
+		respondProblem(
+			c,
 			http.StatusBadRequest,
-			gin.H{
-				"error": gin.H{
-					"code":    "INVALID_REQUEST_BODY",
-					"message": "invalid request body",
-					"details": errors.New("Error while binding JSON: " + err.Error()),
-				},
-			},
+			"INVALID_REQUEST_PARAMETER",
+			"invalid request parameter",
+			"Author ID is required",
 		)
 		return
 	}
 
-	if err := h.authorService.UpdateAuthor(&author); err != nil {
-		c.JSON(
+	var request authorRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondBindingError(c, err)
+		return
+	}
+
+	h.updateAuthor(c, authorID, &request)
+}
+
+// PatchAuthor applies a JSON merge patch to the author,
+// only the fields present on the patch are changed
+func (h *AuthorHandler) PatchAuthor(c *gin.Context) {
+	authorID := c.Param("id")
+	if authorID == "" {
+		respondProblem(
+			c,
 			http.StatusBadRequest,
-			gin.H{
-				"error": gin.H{
-					"code":    "UPDATE_AUTHOR_ERROR",
-					"message": "error while updating author",
-					"details": errors.New("Error while updating author: " + err.Error()),
-				},
-			},
+			"INVALID_REQUEST_PARAMETER",
+			"invalid request parameter",
+			"Author ID is required",
 		)
 		return
 	}
 
+	author, err := h.authorService.FindAuthorByID(c.Request.Context(), authorID)
+	if err != nil {
+		respondError(c, "FIND_AUTHOR_BY_ID_ERROR", "error while finding author by ID", err)
+		return
+	}
+
+	var request authorRequest
+	current := authorRequest{Name: author.Name}
+	if _, ok := bindMergePatch(c, &current, &request); !ok {
+		return
+	}
+
+	h.updateAuthor(c, authorID, &request)
+}
+
+func (h *AuthorHandler) updateAuthor(c *gin.Context, authorID string, request *authorRequest) {
+	version, ok := parseIfMatch(c)
+	if !ok {
+		return
+	}
+
+	var author domain.Author
+	author.ID = authorID
+	author.Name = request.Name
+	author.Version = version
+
+	if err := h.authorService.UpdateAuthor(c.Request.Context(), &author); err != nil {
+		respondError(c, "UPDATE_AUTHOR_ERROR", "error while updating author", err)
+		return
+	}
+
+	setETag(c, author.Version)
 	c.JSON(
 		http.StatusNoContent,
 		gin.H{},
@@ -235,30 +225,23 @@ func (h *AuthorHandler) UpdateAuthor(c *gin.Context) {
 func (h *AuthorHandler) DeleteAuthorByID(c *gin.Context) {
 	authorID := c.Param("id")
 	if authorID == "" {
-		c.JSON(
+		respondProblem(
+			c,
 			http.StatusBadRequest,
-			gin.H{
-				"error": gin.H{
-					"code":    "INVALID_REQUEST_PARAMETER",
-					"message": "invalid request parameter",
-					"details": errors.New("Author ID is required"),
-				},
-			},
+			"INVALID_REQUEST_PARAMETER",
+			"invalid request parameter",
+			"Author ID is required",
 		)
 		return
 	}
 
-	if err := h.authorService.DeleteAuthorByID(authorID); err != nil {
-		c.JSON(
-			http.StatusBadRequest,
-			gin.H{
-				"error": gin.H{
-					"code":    "DELETE_AUTHOR_BY_ID_ERROR",
-					"message": "error while deleting author by ID",
-					"details": errors.New("Error while deleting author by ID: " + err.Error()),
-				},
-			},
-		)
+	version, ok := parseIfMatch(c)
+	if !ok {
+		return
+	}
+
+	if err := h.authorService.DeleteAuthorByID(c.Request.Context(), authorID, version); err != nil {
+		respondError(c, "DELETE_AUTHOR_BY_ID_ERROR", "error while deleting author by ID", err)
 		return
 	}
 
@@ -268,6 +251,50 @@ func (h *AuthorHandler) DeleteAuthorByID(c *gin.Context) {
 	)
 }
 
+// MergeAuthor merges the author into the target author, the
+// author ID keeps resolving to the target after the merge
+func (h *AuthorHandler) MergeAuthor(c *gin.Context) {
+	authorID := c.Param("id")
+	if authorID == "" {
+		respondProblem(
+			c,
+			http.StatusBadRequest,
+			"INVALID_REQUEST_PARAMETER",
+			"invalid request parameter",
+			"Author ID is required",
+		)
+		return
+	}
+
+	var request mergeRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondBindingError(c, err)
+		return
+	}
+
+	version, ok := parseIfMatch(c)
+	if !ok {
+		return
+	}
+
+	target, err := h.authorService.MergeAuthors(c.Request.Context(), authorID, request.TargetID, version)
+	if err != nil {
+		respondError(c, "MERGE_AUTHOR_ERROR", "error while merging author", err)
+		return
+	}
+
+	setETag(c, target.Version)
+
+	c.JSON(
+		http.StatusOK,
+		gin.H{
+			"data": gin.H{
+				"author": h.formatAuthorResponse(target),
+			},
+		},
+	)
+}
+
 func (h *AuthorHandler) formatAuthorResponse(author *domain.Author) *authorResponse {
 	return &authorResponse{
 		ID:   author.ID,
@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/bookcsv"
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	exportFormatNDJSON = "ndjson"
+	exportFormatCSV    = "csv"
+
+	ndjsonContentType = "application/x-ndjson"
+)
+
+// bookExportEncoder writes the books of an export in a given format
+type bookExportEncoder struct {
+	contentType string
+	extension   string
+	encode      func(book *domain.Book) error
+	flush       func() error
+}
+
+// ExportBooks streams every book matching the list filters as NDJSON (one
+// bookResponse per line) or as CSV in the layout accepted by the import.
+// The books are written batch by batch while they are read from the database
+func (h *BookHandler) ExportBooks(c *gin.Context) {
+	encoder, err := h.newBookExportEncoder(c, c.DefaultQuery("format", exportFormatNDJSON))
+	if err != nil {
+		respondProblem(
+			c,
+			http.StatusBadRequest,
+			"INVALID_REQUEST_PARAMETER",
+			"invalid request parameter",
+			err.Error(),
+		)
+		return
+	}
+
+	filter, err := h.parseBookFilter(c)
+	if err != nil {
+		respondProblem(
+			c,
+			http.StatusBadRequest,
+			"INVALID_REQUEST_PARAMETER",
+			"invalid request parameter",
+			"Error while parsing filters: "+err.Error(),
+		)
+		return
+	}
+
+	// The status is only sent with the first batch,
+	// so the errors found before it are still reported as problems
+	started := false
+	start := func() {
+		if started {
+			return
+		}
+		started = true
+
+		c.Header("Content-Type", encoder.contentType)
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"books.%s\"", encoder.extension))
+		c.Status(http.StatusOK)
+	}
+
+	err = h.bookService.ExportBooks(c.Request.Context(), filter, func(books []*domain.Book) error {
+		start()
+
+		for _, book := range books {
+			if err := encoder.encode(book); err != nil {
+				return err
+			}
+		}
+
+		if err := encoder.flush(); err != nil {
+			return err
+		}
+		c.Writer.Flush()
+
+		return nil
+	})
+	if err != nil {
+		if !started {
+			respondError(c, "EXPORT_BOOKS_ERROR", "error while exporting books", err)
+			return
+		}
+
+		// The response is already on its way, the error can only be logged
+		_ = c.Error(err)
+		return
+	}
+
+	start()
+	if err := encoder.flush(); err != nil {
+		_ = c.Error(err)
+	}
+}
+
+func (h *BookHandler) newBookExportEncoder(c *gin.Context, format string) (*bookExportEncoder, error) {
+	switch format {
+	case exportFormatNDJSON:
+		// json.Encoder ends every value with a new line
+		jsonEncoder := json.NewEncoder(c.Writer)
+
+		return &bookExportEncoder{
+			contentType: ndjsonContentType,
+			extension:   exportFormatNDJSON,
+			encode: func(book *domain.Book) error {
+				return jsonEncoder.Encode(h.formatBookResponse(book))
+			},
+			flush: func() error {
+				return nil
+			},
+		}, nil
+	case exportFormatCSV:
+		csvWriter := bookcsv.NewWriter(c.Writer)
+
+		return &bookExportEncoder{
+			contentType: csvContentType,
+			extension:   exportFormatCSV,
+			encode:      csvWriter.Write,
+			flush:       csvWriter.Flush,
+		}, nil
+	default:
+		return nil, fmt.Errorf("format must be either %q or %q", exportFormatNDJSON, exportFormatCSV)
+	}
+}
@@ -0,0 +1,800 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/domain"
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/isbn"
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/repository"
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type BookHandler struct {
+	bookService service.BookService
+}
+
+type bookRequest struct {
+	Title      string `binding:"required"`
+	Synopsis   string `binding:"required"`
+	ISBN       string
+	Publisher  *bookPublisherRequest
+	Series     *bookSeriesRequest
+	Authors    []bookAuthorRequest
+	Categories []bookCategoryRequest
+}
+
+// bookAuthorRequest references an author either by ID or by name
+type bookAuthorRequest struct {
+	ID   string
+	Name string
+}
+
+// bookCategoryRequest references a category either by ID or by name
+type bookCategoryRequest struct {
+	ID   string
+	Name string
+}
+
+// bookPublisherRequest references a publisher either by ID or by name
+type bookPublisherRequest struct {
+	ID   string
+	Name string
+}
+
+// bookSeriesRequest references a series either by ID or by name,
+// with the position of the book inside it
+type bookSeriesRequest struct {
+	ID       string
+	Name     string
+	Position int
+}
+
+type bookResponse struct {
+	ID         string
+	Title      string
+	Synopsis   string
+	ISBN       string
+	ISBN10     string
+	Publisher  *publisherResponse
+	Series     *bookSeriesResponse
+	Authors    []bookAuthorResponse
+	Categories []categoryResponse
+}
+
+// bookAuthorResponse is an author of a book, CreditedAs is the
+// name shown on that book (the author name or one of its aliases)
+type bookAuthorResponse struct {
+	ID         string
+	Name       string
+	CreditedAs string
+}
+
+// bookSeriesResponse is the series of a book, Previous and Next
+// are only filled when a single book is returned
+type bookSeriesResponse struct {
+	ID       string
+	Name     string
+	Position int
+	Previous *bookVolumeResponse
+	Next     *bookVolumeResponse
+}
+
+type bookVolumeResponse struct {
+	ID       string
+	Title    string
+	Position int
+}
+
+// bookChangesResponse tells which authors, categories and publisher were
+// created, which existing ones were reused and which were linked or unlinked
+type bookChangesResponse struct {
+	CreatedAuthors     []authorResponse
+	ReusedAuthors      []authorResponse
+	LinkedAuthors      []authorResponse
+	UnlinkedAuthors    []authorResponse
+	CreatedCategories  []categoryResponse
+	ReusedCategories   []categoryResponse
+	LinkedCategories   []categoryResponse
+	UnlinkedCategories []categoryResponse
+	CreatedPublisher   *publisherResponse
+	ReusedPublisher    *publisherResponse
+	CreatedSeries      *seriesResponse
+	ReusedSeries       *seriesResponse
+}
+
+type bookSearchResponse struct {
+	Book              bookResponse
+	Rank              float64
+	TitleHighlight    string
+	SynopsisHighlight string
+}
+
+func NewBookHandler(bookService service.BookService) *BookHandler {
+	return &BookHandler{bookService}
+}
+
+func (h *BookHandler) CreateBook(c *gin.Context) {
+	var request bookRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondBindingError(c, err)
+		return
+	}
+
+	book := h.formatBookDomain(&request)
+
+	changes, err := h.bookService.CreateBook(c.Request.Context(), book)
+	if err != nil {
+		respondError(c, "CREATE_BOOK_ERROR", "error while creating book", err)
+		return
+	}
+
+	c.JSON(
+		http.StatusCreated,
+		gin.H{
+			"data": gin.H{
+				"message": "Book created successfully",
+				"book":    h.formatBookResponse(book),
+				"changes": h.formatBookChangesResponse(changes),
+			},
+		},
+	)
+}
+
+func (h *BookHandler) FindBookByID(c *gin.Context) {
+	bookID := c.Param("id")
+	if bookID == "" {
+		respondProblem(
+			c,
+			http.StatusBadRequest,
+			"INVALID_REQUEST_PARAMETER",
+			"invalid request parameter",
+			"Book ID is required",
+		)
+		return
+	}
+
+	book, err := h.bookService.FindBookByID(c.Request.Context(), bookID)
+	if err != nil {
+		respondError(c, "FIND_BOOK_BY_ID_ERROR", "error while finding book by ID", err)
+		return
+	}
+
+	h.respondBook(c, book)
+}
+
+func (h *BookHandler) FindBookByTitle(c *gin.Context) {
+	bookTitle := c.Param("title")
+	if bookTitle == "" {
+		respondProblem(
+			c,
+			http.StatusBadRequest,
+			"INVALID_REQUEST_PARAMETER",
+			"invalid request parameter",
+			"Book title is required",
+		)
+		return
+	}
+
+	book, err := h.bookService.FindBookByTitle(c.Request.Context(), bookTitle)
+	if err != nil {
+		respondError(c, "FIND_BOOK_BY_TITLE_ERROR", "error while finding book by title", err)
+		return
+	}
+
+	h.respondBook(c, book)
+}
+
+func (h *BookHandler) FindBookByISBN(c *gin.Context) {
+	bookISBN := c.Param("isbn")
+	if bookISBN == "" {
+		respondProblem(
+			c,
+			http.StatusBadRequest,
+			"INVALID_REQUEST_PARAMETER",
+			"invalid request parameter",
+			"Book ISBN is required",
+		)
+		return
+	}
+
+	book, err := h.bookService.FindBookByISBN(c.Request.Context(), bookISBN)
+	if err != nil {
+		respondError(c, "FIND_BOOK_BY_ISBN_ERROR", "error while finding book by ISBN", err)
+		return
+	}
+
+	h.respondBook(c, book)
+}
+
+// respondBook writes a single book, with the volumes
+// around it when the book belongs to a series
+func (h *BookHandler) respondBook(c *gin.Context, book *domain.Book) {
+	navigation, err := h.bookService.FindVolumeNavigation(c.Request.Context(), book)
+	if err != nil {
+		respondError(c, "FIND_VOLUME_NAVIGATION_ERROR", "error while finding the series volumes", err)
+		return
+	}
+
+	response := h.formatBookResponse(book)
+	h.formatBookNavigationResponse(&response, navigation)
+
+	setETag(c, book.Version)
+
+	c.JSON(
+		http.StatusOK,
+		gin.H{
+			"data": gin.H{
+				"book": response,
+			},
+		},
+	)
+}
+
+// FindSeriesVolumes lists the books of a series ordered by their position
+func (h *BookHandler) FindSeriesVolumes(c *gin.Context) {
+	seriesID := c.Param("id")
+	if seriesID == "" {
+		respondProblem(
+			c,
+			http.StatusBadRequest,
+			"INVALID_REQUEST_PARAMETER",
+			"invalid request parameter",
+			"Series ID is required",
+		)
+		return
+	}
+
+	books, err := h.bookService.FindSeriesVolumes(c.Request.Context(), seriesID)
+	if err != nil {
+		respondError(c, "FIND_SERIES_VOLUMES_ERROR", "error while finding series volumes", err)
+		return
+	}
+
+	volumesResponse := []bookResponse{}
+	for _, book := range books {
+		volumesResponse = append(volumesResponse, h.formatBookResponse(book))
+	}
+
+	c.JSON(
+		http.StatusOK,
+		gin.H{
+			"data": gin.H{
+				"volumes": volumesResponse,
+			},
+		},
+	)
+}
+
+func (h *BookHandler) FindAllBooks(c *gin.Context) {
+	page, err := parsePageRequest(c)
+	if err != nil {
+		respondProblem(
+			c,
+			http.StatusBadRequest,
+			"INVALID_REQUEST_PARAMETER",
+			"invalid request parameter",
+			"Error while parsing pagination: "+err.Error(),
+		)
+		return
+	}
+
+	filter, err := h.parseBookFilter(c)
+	if err != nil {
+		respondProblem(
+			c,
+			http.StatusBadRequest,
+			"INVALID_REQUEST_PARAMETER",
+			"invalid request parameter",
+			"Error while parsing filters: "+err.Error(),
+		)
+		return
+	}
+
+	books, pageInfo, err := h.bookService.FindAllBooks(c.Request.Context(), filter, page)
+	if err != nil {
+		respondError(c, "FIND_ALL_BOOKS_ERROR", "error while finding all books", err)
+		return
+	}
+
+	booksResponse := []bookResponse{}
+	for _, book := range books {
+		booksResponse = append(booksResponse, h.formatBookResponse(book))
+	}
+
+	c.JSON(
+		http.StatusOK,
+		gin.H{
+			"data": gin.H{
+				"books": booksResponse,
+			},
+			"page": formatPageResponse(pageInfo),
+		},
+	)
+}
+
+func (h *BookHandler) SearchBooks(c *gin.Context) {
+	term := c.Query("q")
+	if term == "" {
+		respondProblem(
+			c,
+			http.StatusBadRequest,
+			"INVALID_REQUEST_PARAMETER",
+			"invalid request parameter",
+			"Search term is required",
+		)
+		return
+	}
+
+	limit, errLimit := strconv.Atoi(c.DefaultQuery("limit", "0"))
+	offset, errOffset := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if errLimit != nil || errOffset != nil || limit < 0 || offset < 0 {
+		respondProblem(
+			c,
+			http.StatusBadRequest,
+			"INVALID_REQUEST_PARAMETER",
+			"invalid request parameter",
+			"Limit and offset must be non-negative integers",
+		)
+		return
+	}
+
+	results, err := h.bookService.SearchBooks(c.Request.Context(), term, limit, offset)
+	if err != nil {
+		respondError(c, "SEARCH_BOOKS_ERROR", "error while searching books", err)
+		return
+	}
+
+	resultsResponse := []bookSearchResponse{}
+	for _, result := range results {
+		resultsResponse = append(resultsResponse, bookSearchResponse{
+			Book:              h.formatBookResponse(result.Book),
+			Rank:              result.Rank,
+			TitleHighlight:    result.TitleHighlight,
+			SynopsisHighlight: result.SynopsisHighlight,
+		})
+	}
+
+	c.JSON(
+		http.StatusOK,
+		gin.H{
+			"data": gin.H{
+				"results": resultsResponse,
+			},
+		},
+	)
+}
+
+func (h *BookHandler) UpdateBook(c *gin.Context) {
+	bookID := c.Param("id")
+	if bookID == "" {
+		respondProblem(
+			c,
+			http.StatusBadRequest,
+			"INVALID_REQUEST_PARAMETER",
+			"invalid request parameter",
+			"Book ID is required",
+		)
+		return
+	}
+
+	var request bookRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondBindingError(c, err)
+		return
+	}
+
+	// The "associations" parameter tells whether the authors and categories
+	// sent replace the current ones, are added to them or are removed from them
+	mode := repository.AssociationMode(c.DefaultQuery("associations", string(repository.AssociationReplace)))
+
+	book := h.formatBookDomain(&request)
+	book.ID = bookID
+
+	// PUT is a full replacement, so the lists missing
+	// on the body unlink every author or category
+	if mode == repository.AssociationReplace {
+		if book.Authors == nil {
+			book.Authors = []domain.Author{}
+		}
+		if book.Categories == nil {
+			book.Categories = []domain.Category{}
+		}
+	}
+
+	h.updateBook(c, book, mode)
+}
+
+// PatchBook applies a JSON merge patch to the book, only the fields
+// present on the patch are changed. As on any merge patch, the authors
+// and categories lists sent replace the current ones
+func (h *BookHandler) PatchBook(c *gin.Context) {
+	bookID := c.Param("id")
+	if bookID == "" {
+		respondProblem(
+			c,
+			http.StatusBadRequest,
+			"INVALID_REQUEST_PARAMETER",
+			"invalid request parameter",
+			"Book ID is required",
+		)
+		return
+	}
+
+	bookOnDB, err := h.bookService.FindBookByID(c.Request.Context(), bookID)
+	if err != nil {
+		respondError(c, "FIND_BOOK_BY_ID_ERROR", "error while finding book by ID", err)
+		return
+	}
+
+	var request bookRequest
+	current := h.formatBookRequest(bookOnDB)
+	patch, ok := bindMergePatch(c, &current, &request)
+	if !ok {
+		return
+	}
+
+	// The current publisher and series are merged with their IDs, which are
+	// looked up before the names, so the ones sent only by name must not keep them
+	if request.Publisher != nil && patchNamesWithoutID(patch, "Publisher") {
+		request.Publisher.ID = ""
+	}
+	if request.Series != nil && patchNamesWithoutID(patch, "Series") {
+		request.Series.ID = ""
+	}
+
+	book := h.formatBookDomain(&request)
+	book.ID = bookID
+
+	// The lists not touched by the patch are kept as they are,
+	// while a list set to null by the patch unlinks every record
+	if !patchHasField(patch, "Authors") {
+		book.Authors = nil
+	} else if book.Authors == nil {
+		book.Authors = []domain.Author{}
+	}
+	if !patchHasField(patch, "Categories") {
+		book.Categories = nil
+	} else if book.Categories == nil {
+		book.Categories = []domain.Category{}
+	}
+
+	h.updateBook(c, book, repository.AssociationReplace)
+}
+
+func (h *BookHandler) updateBook(c *gin.Context, book *domain.Book, mode repository.AssociationMode) {
+	version, ok := parseIfMatch(c)
+	if !ok {
+		return
+	}
+	book.Version = version
+
+	changes, err := h.bookService.UpdateBook(c.Request.Context(), book, mode)
+	if err != nil {
+		respondError(c, "UPDATE_BOOK_ERROR", "error while updating book", err)
+		return
+	}
+
+	setETag(c, book.Version)
+	c.JSON(
+		http.StatusOK,
+		gin.H{
+			"data": gin.H{
+				"message": "Book updated successfully",
+				"changes": h.formatBookChangesResponse(changes),
+			},
+		},
+	)
+}
+
+func (h *BookHandler) DeleteBookByID(c *gin.Context) {
+	bookID := c.Param("id")
+	if bookID == "" {
+		respondProblem(
+			c,
+			http.StatusBadRequest,
+			"INVALID_REQUEST_PARAMETER",
+			"invalid request parameter",
+			"Book ID is required",
+		)
+		return
+	}
+
+	version, ok := parseIfMatch(c)
+	if !ok {
+		return
+	}
+
+	if err := h.bookService.DeleteBookByID(c.Request.Context(), bookID, version); err != nil {
+		respondError(c, "DELETE_BOOK_BY_ID_ERROR", "error while deleting book by ID", err)
+		return
+	}
+
+	c.JSON(
+		http.StatusNoContent,
+		gin.H{},
+	)
+}
+
+// parseBookFilter reads the book listing filters from the query string.
+// The author, category and publisher parameters can be repeated to match several values
+// and include_subcategories extends the categories to all of their descendants.
+// created_from and created_to form the half-open range [created_from, created_to):
+// created_to is exclusive, so created_to=2024-06-01 keeps the books created up to the
+// end of 2024-05-31 (the plain dates are the midnight UTC of that day)
+func (h *BookHandler) parseBookFilter(c *gin.Context) (repository.BookFilter, error) {
+	filter := repository.BookFilter{
+		AuthorIDs:      c.QueryArray("author_id"),
+		AuthorNames:    c.QueryArray("author"),
+		CategoryIDs:    c.QueryArray("category_id"),
+		CategoryNames:  c.QueryArray("category"),
+		PublisherIDs:   c.QueryArray("publisher_id"),
+		PublisherNames: c.QueryArray("publisher"),
+		Match:          repository.FilterMatch(c.Query("match")),
+	}
+
+	if value := c.Query("include_subcategories"); value != "" {
+		includeSubcategories, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, fmt.Errorf("include_subcategories: must be a boolean")
+		}
+		filter.IncludeSubcategories = includeSubcategories
+	}
+
+	if value := c.Query("created_from"); value != "" {
+		createdFrom, err := parseFilterDate(value)
+		if err != nil {
+			return filter, fmt.Errorf("created_from: %v", err)
+		}
+		filter.CreatedFrom = &createdFrom
+	}
+
+	if value := c.Query("created_to"); value != "" {
+		createdTo, err := parseFilterDate(value)
+		if err != nil {
+			return filter, fmt.Errorf("created_to: %v", err)
+		}
+		filter.CreatedTo = &createdTo
+	}
+
+	return filter, nil
+}
+
+// parseFilterDate accepts either a full RFC 3339 timestamp or a plain date
+func parseFilterDate(value string) (time.Time, error) {
+	if date, err := time.Parse(time.RFC3339, value); err == nil {
+		return date, nil
+	}
+
+	date, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("must be a RFC 3339 timestamp or a YYYY-MM-DD date")
+	}
+
+	return date, nil
+}
+
+func (h *BookHandler) formatBookDomain(request *bookRequest) *domain.Book {
+	book := &domain.Book{
+		Title:    request.Title,
+		Synopsis: request.Synopsis,
+	}
+
+	// An empty ISBN means the book has none
+	if request.ISBN != "" {
+		bookISBN := request.ISBN
+		book.ISBN = &bookISBN
+	}
+
+	if request.Publisher != nil {
+		book.Publisher = &domain.Publisher{}
+		book.Publisher.ID = request.Publisher.ID
+		book.Publisher.Name = request.Publisher.Name
+	}
+
+	if request.Series != nil {
+		position := request.Series.Position
+		book.Series = &domain.Series{}
+		book.Series.ID = request.Series.ID
+		book.Series.Name = request.Series.Name
+		book.SeriesPosition = &position
+	}
+
+	// Keep the difference between a list not sent (nil)
+	// and an empty list sent to unlink every record
+	if request.Authors != nil {
+		book.Authors = make([]domain.Author, 0, len(request.Authors))
+	}
+	if request.Categories != nil {
+		book.Categories = make([]domain.Category, 0, len(request.Categories))
+	}
+
+	for _, author := range request.Authors {
+		var bookAuthor domain.Author
+		bookAuthor.ID = author.ID
+		bookAuthor.Name = author.Name
+		book.Authors = append(book.Authors, bookAuthor)
+	}
+
+	for _, category := range request.Categories {
+		var bookCategory domain.Category
+		bookCategory.ID = category.ID
+		bookCategory.Name = category.Name
+		book.Categories = append(book.Categories, bookCategory)
+	}
+
+	return book
+}
+
+// formatBookRequest builds the request that would create the given book
+func (h *BookHandler) formatBookRequest(book *domain.Book) bookRequest {
+	request := bookRequest{
+		Title:      book.Title,
+		Synopsis:   book.Synopsis,
+		Authors:    []bookAuthorRequest{},
+		Categories: []bookCategoryRequest{},
+	}
+
+	if book.ISBN != nil {
+		request.ISBN = *book.ISBN
+	}
+
+	if book.Publisher != nil {
+		request.Publisher = &bookPublisherRequest{
+			ID:   book.Publisher.ID,
+			Name: book.Publisher.Name,
+		}
+	}
+
+	if book.Series != nil && book.SeriesPosition != nil {
+		request.Series = &bookSeriesRequest{
+			ID:       book.Series.ID,
+			Name:     book.Series.Name,
+			Position: *book.SeriesPosition,
+		}
+	}
+
+	for _, author := range book.Authors {
+		request.Authors = append(request.Authors, bookAuthorRequest{
+			ID:   author.ID,
+			Name: author.Name,
+		})
+	}
+
+	for _, category := range book.Categories {
+		request.Categories = append(request.Categories, bookCategoryRequest{
+			ID:   category.ID,
+			Name: category.Name,
+		})
+	}
+
+	return request
+}
+
+func (h *BookHandler) formatBookResponse(book *domain.Book) bookResponse {
+	response := bookResponse{
+		ID:         book.ID,
+		Title:      book.Title,
+		Synopsis:   book.Synopsis,
+		Authors:    []bookAuthorResponse{},
+		Categories: formatCategoriesResponse(book.Categories),
+	}
+
+	for _, author := range book.Authors {
+		response.Authors = append(response.Authors, bookAuthorResponse{
+			ID:         author.ID,
+			Name:       author.Name,
+			CreditedAs: book.CreditedName(author),
+		})
+	}
+
+	if book.ISBN != nil {
+		response.ISBN = *book.ISBN
+		response.ISBN10, _ = isbn.ToISBN10(*book.ISBN)
+	}
+
+	if book.Publisher != nil {
+		response.Publisher = formatPublisherResponse(book.Publisher)
+	}
+
+	if book.Series != nil && book.SeriesPosition != nil {
+		response.Series = &bookSeriesResponse{
+			ID:       book.Series.ID,
+			Name:     book.Series.Name,
+			Position: *book.SeriesPosition,
+		}
+	}
+
+	return response
+}
+
+func (h *BookHandler) formatBookChangesResponse(changes *service.BookChanges) bookChangesResponse {
+	return bookChangesResponse{
+		CreatedAuthors:     formatAuthorsResponse(changes.CreatedAuthors),
+		ReusedAuthors:      formatAuthorsResponse(changes.ReusedAuthors),
+		LinkedAuthors:      formatAuthorsResponse(changes.LinkedAuthors),
+		UnlinkedAuthors:    formatAuthorsResponse(changes.UnlinkedAuthors),
+		CreatedCategories:  formatCategoriesResponse(changes.CreatedCategories),
+		ReusedCategories:   formatCategoriesResponse(changes.ReusedCategories),
+		LinkedCategories:   formatCategoriesResponse(changes.LinkedCategories),
+		UnlinkedCategories: formatCategoriesResponse(changes.UnlinkedCategories),
+		CreatedPublisher:   formatPublisherResponse(changes.CreatedPublisher),
+		ReusedPublisher:    formatPublisherResponse(changes.ReusedPublisher),
+		CreatedSeries:      formatSeriesResponse(changes.CreatedSeries),
+		ReusedSeries:       formatSeriesResponse(changes.ReusedSeries),
+	}
+}
+
+// formatBookNavigationResponse adds the previous and next volumes to the book response
+func (h *BookHandler) formatBookNavigationResponse(response *bookResponse, navigation *service.VolumeNavigation) {
+	if response.Series == nil {
+		return
+	}
+
+	response.Series.Previous = formatBookVolumeResponse(navigation.Previous)
+	response.Series.Next = formatBookVolumeResponse(navigation.Next)
+}
+
+func formatBookVolumeResponse(book *domain.Book) *bookVolumeResponse {
+	if book == nil || book.SeriesPosition == nil {
+		return nil
+	}
+
+	return &bookVolumeResponse{
+		ID:       book.ID,
+		Title:    book.Title,
+		Position: *book.SeriesPosition,
+	}
+}
+
+func formatAuthorsResponse(authors []domain.Author) []authorResponse {
+	response := []authorResponse{}
+	for _, author := range authors {
+		response = append(response, authorResponse{
+			ID:   author.ID,
+			Name: author.Name,
+		})
+	}
+
+	return response
+}
+
+// formatPublisherResponse keeps a missing publisher as nil
+func formatPublisherResponse(publisher *domain.Publisher) *publisherResponse {
+	if publisher == nil {
+		return nil
+	}
+
+	return &publisherResponse{
+		ID:   publisher.ID,
+		Name: publisher.Name,
+	}
+}
+
+// formatSeriesResponse keeps a missing series as nil
+func formatSeriesResponse(series *domain.Series) *seriesResponse {
+	if series == nil {
+		return nil
+	}
+
+	return &seriesResponse{
+		ID:   series.ID,
+		Name: series.Name,
+	}
+}
+
+func formatCategoriesResponse(categories []domain.Category) []categoryResponse {
+	response := []categoryResponse{}
+	for _, category := range categories {
+		response = append(response, categoryResponse{
+			ID:       category.ID,
+			Name:     category.Name,
+			ParentID: category.ParentID,
+		})
+	}
+
+	return response
+}
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/domain"
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/repository"
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// fakeBookService returns book on the lookups and keeps the book sent
+// to UpdateBook, the methods not used by the tests are left unimplemented
+type fakeBookService struct {
+	service.BookService
+	book    *domain.Book
+	updated *domain.Book
+}
+
+func (s *fakeBookService) FindBookByID(ctx context.Context, id string) (*domain.Book, error) {
+	return s.book, nil
+}
+
+func (s *fakeBookService) UpdateBook(ctx context.Context, book *domain.Book, mode repository.AssociationMode) (*service.BookChanges, error) {
+	s.updated = book
+	return &service.BookChanges{}, nil
+}
+
+// patchBook sends the merge patch to PatchBook and returns the book given to the service
+func patchBook(t *testing.T, book *domain.Book, body string) *domain.Book {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	bookService := &fakeBookService{book: book}
+	router := gin.New()
+	router.PATCH("/books/:id", NewBookHandler(bookService).PatchBook)
+
+	request := httptest.NewRequest(http.MethodPatch, "/books/"+book.ID, strings.NewReader(body))
+	request.Header.Set("Content-Type", mergePatchContentType)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("PATCH %s = %d %s, want 200", body, recorder.Code, recorder.Body.String())
+	}
+
+	return bookService.updated
+}
+
+func TestPatchBookPublisher(t *testing.T) {
+	tests := []struct {
+		name     string
+		patch    string
+		wantID   string
+		wantName string
+	}{
+		{name: "other field keeps the publisher", patch: `{"title":"Other title"}`, wantID: "publisher-id", wantName: "Rocco"},
+		{name: "publisher switched by name", patch: `{"publisher":{"name":"Other"}}`, wantID: "", wantName: "Other"},
+		{name: "publisher switched by ID", patch: `{"publisher":{"id":"other-id"}}`, wantID: "other-id", wantName: "Rocco"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			book := &domain.Book{Title: "Title", Synopsis: "Synopsis"}
+			book.ID = "book-id"
+			book.Publisher = &domain.Publisher{Name: "Rocco"}
+			book.Publisher.ID = "publisher-id"
+
+			updated := patchBook(t, book, tt.patch)
+			if updated.Publisher == nil {
+				t.Fatalf("the publisher was dropped")
+			}
+			if updated.Publisher.ID != tt.wantID || updated.Publisher.Name != tt.wantName {
+				t.Errorf("publisher = {%q, %q}, want {%q, %q}", updated.Publisher.ID, updated.Publisher.Name, tt.wantID, tt.wantName)
+			}
+		})
+	}
+}
+
+func TestPatchBookSeries(t *testing.T) {
+	tests := []struct {
+		name         string
+		patch        string
+		wantID       string
+		wantName     string
+		wantPosition int
+	}{
+		{name: "other field keeps the series", patch: `{"title":"Other title"}`, wantID: "series-id", wantName: "Duna", wantPosition: 2},
+		{name: "series switched by name", patch: `{"series":{"name":"Fundação"}}`, wantID: "", wantName: "Fundação", wantPosition: 2},
+		{name: "series switched by name and position", patch: `{"series":{"name":"Fundação","position":1}}`, wantID: "", wantName: "Fundação", wantPosition: 1},
+		{name: "position changed", patch: `{"series":{"position":3}}`, wantID: "series-id", wantName: "Duna", wantPosition: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			position := 2
+			book := &domain.Book{Title: "Title", Synopsis: "Synopsis", SeriesPosition: &position}
+			book.ID = "book-id"
+			book.Series = &domain.Series{Name: "Duna"}
+			book.Series.ID = "series-id"
+
+			updated := patchBook(t, book, tt.patch)
+			if updated.Series == nil || updated.SeriesPosition == nil {
+				t.Fatalf("the series was dropped")
+			}
+			if updated.Series.ID != tt.wantID || updated.Series.Name != tt.wantName || *updated.SeriesPosition != tt.wantPosition {
+				t.Errorf(
+					"series = {%q, %q, %d}, want {%q, %q, %d}",
+					updated.Series.ID, updated.Series.Name, *updated.SeriesPosition,
+					tt.wantID, tt.wantName, tt.wantPosition,
+				)
+			}
+		})
+	}
+}
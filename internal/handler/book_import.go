@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+const csvContentType = "text/csv"
+
+// maxImportSize is the largest CSV accepted by the import endpoint,
+// bigger catalogues should be loaded with the import command
+const maxImportSize = 10 << 20
+
+type bookImportResponse struct {
+	DryRun   bool
+	Imported int
+	Failed   int
+	Rows     []bookImportRowResponse
+}
+
+// bookImportRowResponse is the outcome of a CSV row, on a dry run
+// the book and its changes are only what would have been created
+type bookImportRowResponse struct {
+	Line     int
+	Imported bool
+	Book     *bookResponse
+	Changes  *bookChangesResponse
+	Error    string
+}
+
+func (h *BookHandler) ImportBooks(c *gin.Context) {
+	if c.ContentType() != csvContentType {
+		respondProblem(
+			c,
+			http.StatusUnsupportedMediaType,
+			"UNSUPPORTED_MEDIA_TYPE",
+			"unsupported media type",
+			"The request body must be sent as "+csvContentType,
+		)
+		return
+	}
+
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+	if err != nil {
+		respondProblem(
+			c,
+			http.StatusBadRequest,
+			"INVALID_REQUEST_PARAMETER",
+			"invalid request parameter",
+			"dry_run must be a boolean",
+		)
+		return
+	}
+
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+
+	report, err := h.bookService.ImportBooks(c.Request.Context(), body, dryRun)
+	if err != nil {
+		var maxBytesError *http.MaxBytesError
+		if errors.As(err, &maxBytesError) {
+			respondProblem(
+				c,
+				http.StatusRequestEntityTooLarge,
+				"REQUEST_TOO_LARGE",
+				"request too large",
+				"The CSV must have at most "+strconv.Itoa(maxImportSize>>20)+" MB",
+			)
+			return
+		}
+
+		respondError(c, "IMPORT_BOOKS_ERROR", "error while importing books", err)
+		return
+	}
+
+	c.JSON(
+		http.StatusOK,
+		gin.H{
+			"data": gin.H{
+				"import": h.formatBookImportResponse(c, report),
+			},
+		},
+	)
+}
+
+func (h *BookHandler) formatBookImportResponse(c *gin.Context, report *service.BookImportReport) bookImportResponse {
+	rows := make([]bookImportRowResponse, 0, len(report.Results))
+	for _, result := range report.Results {
+		row := bookImportRowResponse{
+			Line:     result.Line,
+			Imported: result.Err == nil,
+		}
+
+		if result.Err != nil {
+			row.Error = result.Err.Error()
+
+			// Internal errors are logged but their details are not exposed
+			if statusFromError(result.Err) >= http.StatusInternalServerError {
+				_ = c.Error(result.Err)
+				row.Error = "An unexpected error occurred while importing the row"
+			}
+		} else {
+			book := h.formatBookResponse(result.Book)
+			changes := h.formatBookChangesResponse(result.Changes)
+			row.Book = &book
+			row.Changes = &changes
+		}
+
+		rows = append(rows, row)
+	}
+
+	return bookImportResponse{
+		DryRun:   report.DryRun,
+		Imported: report.Imported,
+		Failed:   report.Failed,
+		Rows:     rows,
+	}
+}
@@ -1,7 +1,6 @@
 package handler
 
 import (
-	"errors"
 	"net/http"
 
 	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/domain"
@@ -14,12 +13,27 @@ type CategoryHandler struct {
 }
 
 type categoryRequest struct {
-	Name string `binding:"required"`
+	Name     string `binding:"required"`
+	ParentID *string
 }
 
 type categoryResponse struct {
-	ID   string
-	Name string
+	ID       string
+	Name     string
+	ParentID *string
+}
+
+// categoryWithPathResponse adds the breadcrumb, from the root
+// category down to the category itself
+type categoryWithPathResponse struct {
+	categoryResponse
+	Path []categoryResponse
+}
+
+type categoryTreeResponse struct {
+	ID       string
+	Name     string
+	Children []*categoryTreeResponse
 }
 
 func NewCategoryHandler(categoryService service.CategoryService) *CategoryHandler {
@@ -29,33 +43,16 @@ func NewCategoryHandler(categoryService service.CategoryService) *CategoryHandle
 func (h *CategoryHandler) CreateCategory(c *gin.Context) {
 	var request categoryRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(
-			http.StatusBadRequest,
-			gin.H{
-				"error": gin.H{
-					"code":    "INVALID_REQUEST_BODY",
-					"message": "invalid request body",
-					"details": errors.New("Error while binding JSON: " + err.Error()),
-				},
-			},
-		)
+		respondBindingError(c, err)
 		return
 	}
 
 	var category domain.Category
 	category.Name = request.Name
+	category.ParentID = request.ParentID
 
-	if err := h.categoryService.CreateCategory(&category); err != nil {
-		c.JSON(
-			http.StatusBadRequest,
-			gin.H{
-				"error": gin.H{
-					"code":    "CREATE_CATEGORY_ERROR",
-					"message": "error while creating category",
-					"details": errors.New("Error while creating category: " + err.Error()),
-				},
-			},
-		)
+	if err := h.categoryService.CreateCategory(c.Request.Context(), &category); err != nil {
+		respondError(c, "CREATE_CATEGORY_ERROR", "error while creating category", err)
 		return
 	}
 
@@ -72,72 +69,115 @@ func (h *CategoryHandler) CreateCategory(c *gin.Context) {
 func (h *CategoryHandler) FindCategoryByID(c *gin.Context) {
 	id := c.Param("id")
 	if id == "" {
-		c.JSON(
+		respondProblem(
+			c,
 			http.StatusBadRequest,
-			gin.H{
-				"error": gin.H{
-					"code":    "INVALID_REQUEST_PARAMETER",
-					"message": "invalid request parameter",
-					"details": "ID is required",
-				},
-			},
+			"INVALID_REQUEST_PARAMETER",
+			"invalid request parameter",
+			"ID is required",
 		)
 		return
 	}
 
-	category, err := h.categoryService.FindCategoryByID(id)
+	category, err := h.categoryService.FindCategoryByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(
-			http.StatusNotFound,
-			gin.H{
-				"error": gin.H{
-					"code":    "CATEGORY_NOT_FOUND",
-					"message": "error while finding category by ID",
-					"details": errors.New("Error while finding category by ID: " + err.Error()),
-				},
-			},
+		respondError(c, "FIND_CATEGORY_BY_ID_ERROR", "error while finding category by ID", err)
+		return
+	}
+
+	h.respondCategory(c, category)
+}
+
+func (h *CategoryHandler) FindCategoryByName(c *gin.Context) {
+	name := c.Param("name")
+	if name == "" {
+		respondProblem(
+			c,
+			http.StatusBadRequest,
+			"INVALID_REQUEST_PARAMETER",
+			"invalid request parameter",
+			"Name is required",
 		)
 		return
 	}
 
+	category, err := h.categoryService.FindCategoryByName(c.Request.Context(), name)
+	if err != nil {
+		respondError(c, "FIND_CATEGORY_BY_NAME_ERROR", "error while finding category by name", err)
+		return
+	}
+
+	h.respondCategory(c, category)
+}
+
+// respondCategory writes the category along with its breadcrumb
+func (h *CategoryHandler) respondCategory(c *gin.Context, category *domain.Category) {
+	path, err := h.categoryService.FindCategoryPath(c.Request.Context(), category.ID)
+	if err != nil {
+		respondError(c, "FIND_CATEGORY_PATH_ERROR", "error while finding the category path", err)
+		return
+	}
+
+	response := categoryWithPathResponse{
+		categoryResponse: h.formatCategoryDataReturn(category),
+		Path:             []categoryResponse{},
+	}
+	for _, ancestor := range path {
+		response.Path = append(response.Path, h.formatCategoryDataReturn(ancestor))
+	}
+
+	setETag(c, category.Version)
+
 	c.JSON(
 		http.StatusOK,
 		gin.H{
 			"data": gin.H{
-				"category": h.formatCategoryDataReturn(category),
+				"category": response,
 			},
 		},
 	)
 }
 
-func (h *CategoryHandler) FindCategoryByName(c *gin.Context) {
-	name := c.Param("name")
-	if name == "" {
-		c.JSON(
-			http.StatusBadRequest,
-			gin.H{
-				"error": gin.H{
-					"code":    "INVALID_REQUEST_PARAMETER",
-					"message": "invalid request parameter",
-					"details": errors.New("Name is required"),
-				},
+// FindCategoryTree returns every category nested under its parent
+func (h *CategoryHandler) FindCategoryTree(c *gin.Context) {
+	tree, err := h.categoryService.FindCategoryTree(c.Request.Context())
+	if err != nil {
+		respondError(c, "FIND_CATEGORY_TREE_ERROR", "error while finding the category tree", err)
+		return
+	}
+
+	treeResponse := []*categoryTreeResponse{}
+	for _, node := range tree {
+		treeResponse = append(treeResponse, h.formatCategoryTreeResponse(node))
+	}
+
+	c.JSON(
+		http.StatusOK,
+		gin.H{
+			"data": gin.H{
+				"categories": treeResponse,
 			},
+		},
+	)
+}
+
+// FindCategorySubtree returns the category with its descendants nested under it
+func (h *CategoryHandler) FindCategorySubtree(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		respondProblem(
+			c,
+			http.StatusBadRequest,
+			"INVALID_REQUEST_PARAMETER",
+			"invalid request parameter",
+			"ID is required",
 		)
 		return
 	}
 
-	category, err := h.categoryService.FindCategoryByName(name)
+	subtree, err := h.categoryService.FindCategorySubtree(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(
-			http.StatusNotFound,
-			gin.H{
-				"error": gin.H{
-					"code":    "CATEGORY_NOT_FOUND",
-					"message": "error while finding category by name",
-					"details": errors.New("Error while finding category by name: " + err.Error()),
-				},
-			},
-		)
+		respondError(c, "FIND_CATEGORY_SUBTREE_ERROR", "error while finding the category subtree", err)
 		return
 	}
 
@@ -145,39 +185,28 @@ func (h *CategoryHandler) FindCategoryByName(c *gin.Context) {
 		http.StatusOK,
 		gin.H{
 			"data": gin.H{
-				"category": h.formatCategoryDataReturn(category),
+				"category": h.formatCategoryTreeResponse(subtree),
 			},
 		},
 	)
 }
 
 func (h *CategoryHandler) FindAllCategories(c *gin.Context) {
-	categories, err := h.categoryService.FindAllCategories()
+	page, err := parsePageRequest(c)
 	if err != nil {
-		c.JSON(
-			http.StatusNotFound,
-			gin.H{
-				"error": gin.H{
-					"code":    "CATEGORIES_NOT_FOUND",
-					"message": "error while finding all categories",
-					"details": errors.New("Error while finding all categories: " + err.Error()),
-				},
-			},
+		respondProblem(
+			c,
+			http.StatusBadRequest,
+			"INVALID_REQUEST_PARAMETER",
+			"invalid request parameter",
+			"Error while parsing pagination: "+err.Error(),
 		)
 		return
 	}
 
-	if len(categories) == 0 {
-		c.JSON(
-			http.StatusNotFound,
-			gin.H{
-				"error": gin.H{
-					"code":    "CATEGORIES_NOT_FOUND",
-					"message": "categories not found",
-					"details": "Categories not found in the database",
-				},
-			},
-		)
+	categories, pageInfo, err := h.categoryService.FindAllCategories(c.Request.Context(), page)
+	if err != nil {
+		respondError(c, "FIND_ALL_CATEGORIES_ERROR", "error while finding all categories", err)
 		return
 	}
 
@@ -192,40 +221,81 @@ func (h *CategoryHandler) FindAllCategories(c *gin.Context) {
 			"data": gin.H{
 				"categories": catFormated,
 			},
+			"page": formatPageResponse(pageInfo),
 		},
 	)
 }
 
 func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
-	var category domain.Category
-	if err := c.ShouldBindJSON(&category); err != nil {
-		c.JSON(
+	categoryID := c.Param("id")
+	if categoryID == "" {
+		respondProblem(
+			c,
 			http.StatusBadRequest,
-			gin.H{
-				"error": gin.H{
-					"code":    "INVALID_REQUEST_BODY",
-					"message": "invalid request body",
-					"details": errors.New("Error while binding JSON: " + err.Error()),
-				},
-			},
+			"INVALID_REQUEST_PARAMETER",
+			"invalid request parameter",
+			"Category ID is required",
 		)
 		return
 	}
 
-	if err := h.categoryService.UpdateCategory(&category); err != nil {
-		c.JSON(
+	var request categoryRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondBindingError(c, err)
+		return
+	}
+
+	h.updateCategory(c, categoryID, &request)
+}
+
+// PatchCategory applies a JSON merge patch to the category,
+// only the fields present on the patch are changed
+func (h *CategoryHandler) PatchCategory(c *gin.Context) {
+	categoryID := c.Param("id")
+	if categoryID == "" {
+		respondProblem(
+			c,
 			http.StatusBadRequest,
-			gin.H{
-				"error": gin.H{
-					"code":    "UPDATE_CATEGORY_ERROR",
-					"message": "error while updating category",
-					"details": errors.New("Error while updating category: " + err.Error()),
-				},
-			},
+			"INVALID_REQUEST_PARAMETER",
+			"invalid request parameter",
+			"Category ID is required",
 		)
 		return
 	}
 
+	category, err := h.categoryService.FindCategoryByID(c.Request.Context(), categoryID)
+	if err != nil {
+		respondError(c, "FIND_CATEGORY_BY_ID_ERROR", "error while finding category by ID", err)
+		return
+	}
+
+	var request categoryRequest
+	current := categoryRequest{Name: category.Name, ParentID: category.ParentID}
+	if _, ok := bindMergePatch(c, &current, &request); !ok {
+		return
+	}
+
+	h.updateCategory(c, categoryID, &request)
+}
+
+func (h *CategoryHandler) updateCategory(c *gin.Context, categoryID string, request *categoryRequest) {
+	version, ok := parseIfMatch(c)
+	if !ok {
+		return
+	}
+
+	var category domain.Category
+	category.ID = categoryID
+	category.Name = request.Name
+	category.ParentID = request.ParentID
+	category.Version = version
+
+	if err := h.categoryService.UpdateCategory(c.Request.Context(), &category); err != nil {
+		respondError(c, "UPDATE_CATEGORY_ERROR", "error while updating category", err)
+		return
+	}
+
+	setETag(c, category.Version)
 	c.JSON(
 		http.StatusNoContent,
 		gin.H{},
@@ -235,30 +305,23 @@ func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
 func (h *CategoryHandler) DeleteCategoryByID(c *gin.Context) {
 	id := c.Param("id")
 	if id == "" {
-		c.JSON(
+		respondProblem(
+			c,
 			http.StatusBadRequest,
-			gin.H{
-				"error": gin.H{
-					"code":    "INVALID_REQUEST_PARAMETER",
-					"message": "invalid request parameter",
-					"details": errors.New("ID is required"),
-				},
-			},
+			"INVALID_REQUEST_PARAMETER",
+			"invalid request parameter",
+			"ID is required",
 		)
 		return
 	}
 
-	if err := h.categoryService.DeleteCategoryByID(id); err != nil {
-		c.JSON(
-			http.StatusBadRequest,
-			gin.H{
-				"error": gin.H{
-					"code":    "DELETE_CATEGORY_ERROR",
-					"message": "error while deleting category",
-					"details": errors.New("Error while deleting category: " + err.Error()),
-				},
-			},
-		)
+	version, ok := parseIfMatch(c)
+	if !ok {
+		return
+	}
+
+	if err := h.categoryService.DeleteCategoryByID(c.Request.Context(), id, version); err != nil {
+		respondError(c, "DELETE_CATEGORY_ERROR", "error while deleting category", err)
 		return
 	}
 
@@ -268,9 +331,58 @@ func (h *CategoryHandler) DeleteCategoryByID(c *gin.Context) {
 	)
 }
 
+// MergeCategory merges the category into the target category, the
+// category ID keeps resolving to the target after the merge
+func (h *CategoryHandler) MergeCategory(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		respondProblem(
+			c,
+			http.StatusBadRequest,
+			"INVALID_REQUEST_PARAMETER",
+			"invalid request parameter",
+			"ID is required",
+		)
+		return
+	}
+
+	var request mergeRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondBindingError(c, err)
+		return
+	}
+
+	version, ok := parseIfMatch(c)
+	if !ok {
+		return
+	}
+
+	target, err := h.categoryService.MergeCategories(c.Request.Context(), id, request.TargetID, version)
+	if err != nil {
+		respondError(c, "MERGE_CATEGORY_ERROR", "error while merging category", err)
+		return
+	}
+
+	h.respondCategory(c, target)
+}
+
 func (h *CategoryHandler) formatCategoryDataReturn(category *domain.Category) categoryResponse {
 	return categoryResponse{
-		ID:   category.ID,
-		Name: category.Name,
+		ID:       category.ID,
+		Name:     category.Name,
+		ParentID: category.ParentID,
 	}
 }
+
+func (h *CategoryHandler) formatCategoryTreeResponse(node *service.CategoryNode) *categoryTreeResponse {
+	response := &categoryTreeResponse{
+		ID:       node.Category.ID,
+		Name:     node.Category.Name,
+		Children: []*categoryTreeResponse{},
+	}
+	for _, child := range node.Children {
+		response.Children = append(response.Children, h.formatCategoryTreeResponse(child))
+	}
+
+	return response
+}
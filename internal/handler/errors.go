@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/domain"
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/problem"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// problemViolation is a field refused by the validation of the request
+type problemViolation = problem.Violation
+
+// statusFromError maps the domain errors returned by the services
+// to the HTTP status code sent to the client
+func statusFromError(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrValidation):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, domain.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrPreconditionFailed):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// respondError writes the error returned by a service using the status
+// code of its domain error. Internal errors are logged but their details
+// are not exposed to the client
+func respondError(c *gin.Context, code string, title string, err error) {
+	status := statusFromError(err)
+
+	detail := err.Error()
+	switch status {
+	case http.StatusInternalServerError:
+		_ = c.Error(err)
+		detail = "An unexpected error occurred, please try again later"
+	case http.StatusGatewayTimeout:
+		_ = c.Error(err)
+		detail = "The request took too long to be processed"
+	}
+
+	respondProblem(c, status, code, title, detail, validationViolations(err)...)
+}
+
+// respondBindingError writes the error returned while binding the request body,
+// the failed binding rules are reported as violations
+func respondBindingError(c *gin.Context, err error) {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		respondProblem(
+			c,
+			http.StatusBadRequest,
+			"INVALID_REQUEST_BODY",
+			"invalid request body",
+			"Error while binding JSON: "+err.Error(),
+		)
+		return
+	}
+
+	violations := make([]problemViolation, 0, len(validationErrors))
+	for _, fieldError := range validationErrors {
+		violations = append(violations, problemViolation{
+			Field:   fieldError.Field(),
+			Message: "failed on the '" + fieldError.Tag() + "' rule",
+		})
+	}
+
+	respondProblem(
+		c,
+		http.StatusUnprocessableEntity,
+		"INVALID_REQUEST_BODY",
+		"invalid request body",
+		"The request body has invalid fields",
+		violations...,
+	)
+}
+
+func respondProblem(c *gin.Context, status int, code, title, detail string, violations ...problemViolation) {
+	problem.Respond(c, status, code, title, detail, violations...)
+}
+
+// validationViolations collects every domain.ValidationError in the error tree
+func validationViolations(err error) []problemViolation {
+	var violations []problemViolation
+
+	var walk func(err error)
+	walk = func(err error) {
+		switch e := err.(type) {
+		case nil:
+			return
+		case *domain.ValidationError:
+			violations = append(violations, problemViolation{
+				Field:   e.Field,
+				Message: e.Message,
+			})
+		case interface{ Unwrap() []error }:
+			for _, inner := range e.Unwrap() {
+				walk(inner)
+			}
+		case interface{ Unwrap() error }:
+			walk(e.Unwrap())
+		}
+	}
+	walk(err)
+
+	return violations
+}
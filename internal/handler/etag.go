@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// setETag sends the record version as its entity tag
+func setETag(c *gin.Context, version int64) {
+	c.Header("ETag", fmt.Sprintf("%q", strconv.FormatInt(version, 10)))
+}
+
+// parseIfMatch returns the version sent on the If-Match header.
+// Zero is returned when the header is missing or is "*",
+// meaning the change does not depend on a specific version.
+// On failure the error response is already written
+func parseIfMatch(c *gin.Context) (int64, bool) {
+	value := strings.TrimSpace(c.GetHeader("If-Match"))
+	if value == "" || value == "*" {
+		return 0, true
+	}
+
+	version, err := strconv.ParseInt(strings.Trim(value, `"`), 10, 64)
+	if err != nil || version <= 0 || strings.HasPrefix(value, "W/") {
+		respondProblem(
+			c,
+			http.StatusBadRequest,
+			"INVALID_IF_MATCH_HEADER",
+			"invalid If-Match header",
+			"The If-Match header must contain a single strong ETag returned by the API",
+		)
+		return 0, false
+	}
+
+	return version, true
+}
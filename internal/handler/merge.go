@@ -0,0 +1,6 @@
+package handler
+
+// mergeRequest names the record the one on the URL is merged into
+type mergeRequest struct {
+	TargetID string `binding:"required"`
+}
@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+const mergePatchContentType = "application/merge-patch+json"
+
+// bindMergePatch applies the RFC 7386 merge patch sent on the request body
+// to the current representation of the resource and binds the merged document
+// to dst, validating it with the same binding rules used on creation.
+// The decoded patch is returned so the caller can check which fields it touched.
+// On failure the error response is already written
+func bindMergePatch(c *gin.Context, current interface{}, dst interface{}) (map[string]interface{}, bool) {
+	if c.ContentType() != mergePatchContentType {
+		respondProblem(
+			c,
+			http.StatusUnsupportedMediaType,
+			"UNSUPPORTED_MEDIA_TYPE",
+			"unsupported media type",
+			"The request body must be sent as "+mergePatchContentType,
+		)
+		return nil, false
+	}
+
+	rawPatch, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		respondBindingError(c, err)
+		return nil, false
+	}
+
+	var patch interface{}
+	if err := json.Unmarshal(rawPatch, &patch); err != nil {
+		respondBindingError(c, err)
+		return nil, false
+	}
+
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		respondBindingError(c, fmt.Errorf("the merge patch must be a JSON object"))
+		return nil, false
+	}
+
+	rawCurrent, err := json.Marshal(current)
+	if err != nil {
+		respondError(c, "MERGE_PATCH_ERROR", "error while applying the merge patch", err)
+		return nil, false
+	}
+
+	var target interface{}
+	if err := json.Unmarshal(rawCurrent, &target); err != nil {
+		respondError(c, "MERGE_PATCH_ERROR", "error while applying the merge patch", err)
+		return nil, false
+	}
+
+	merged, err := json.Marshal(applyMergePatch(target, patchObject))
+	if err != nil {
+		respondError(c, "MERGE_PATCH_ERROR", "error while applying the merge patch", err)
+		return nil, false
+	}
+
+	if err := json.Unmarshal(merged, dst); err != nil {
+		respondBindingError(c, err)
+		return nil, false
+	}
+
+	if err := binding.Validator.ValidateStruct(dst); err != nil {
+		respondBindingError(c, err)
+		return nil, false
+	}
+
+	return patchObject, true
+}
+
+// applyMergePatch implements the RFC 7386 algorithm. As the request
+// bodies are bound ignoring the case of the keys, the patch keys
+// replace the target keys that only differ by case
+func applyMergePatch(target interface{}, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+
+	for key, value := range patchObject {
+		targetKey := key
+		if existing, found := findKey(targetObject, key); found {
+			targetKey = existing
+		}
+
+		if value == nil {
+			delete(targetObject, targetKey)
+			continue
+		}
+
+		targetObject[targetKey] = applyMergePatch(targetObject[targetKey], value)
+	}
+
+	return targetObject
+}
+
+// findKey looks for the key on the object, ignoring the case
+func findKey(object map[string]interface{}, key string) (string, bool) {
+	if _, ok := object[key]; ok {
+		return key, true
+	}
+
+	for existing := range object {
+		if strings.EqualFold(existing, key) {
+			return existing, true
+		}
+	}
+
+	return "", false
+}
+
+// patchHasField tells if the merge patch touches the given field
+func patchHasField(patch map[string]interface{}, field string) bool {
+	_, found := findKey(patch, field)
+	return found
+}
+
+// patchNamesWithoutID tells if the merge patch sets the name of the nested
+// object on the given field without its ID, meaning another record is referenced
+func patchNamesWithoutID(patch map[string]interface{}, field string) bool {
+	key, found := findKey(patch, field)
+	if !found {
+		return false
+	}
+
+	nested, ok := patch[key].(map[string]interface{})
+	return ok && patchHasField(nested, "Name") && !patchHasField(nested, "ID")
+}
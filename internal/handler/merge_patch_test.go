@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestApplyMergePatch(t *testing.T) {
+	// The cases of RFC 7386 appendix A, plus the keys differing by case
+	tests := []struct {
+		name   string
+		target string
+		patch  string
+		want   string
+	}{
+		{name: "replace a member", target: `{"a":"b"}`, patch: `{"a":"c"}`, want: `{"a":"c"}`},
+		{name: "add a member", target: `{"a":"b"}`, patch: `{"b":"c"}`, want: `{"a":"b","b":"c"}`},
+		{name: "remove a member", target: `{"a":"b"}`, patch: `{"a":null}`, want: `{}`},
+		{name: "remove one of the members", target: `{"a":"b","b":"c"}`, patch: `{"a":null}`, want: `{"b":"c"}`},
+		{name: "replace an array", target: `{"a":["b"]}`, patch: `{"a":"c"}`, want: `{"a":"c"}`},
+		{name: "replace by an array", target: `{"a":"c"}`, patch: `{"a":["b"]}`, want: `{"a":["b"]}`},
+		{name: "merge a nested object", target: `{"a":{"b":"c"}}`, patch: `{"a":{"b":"d","c":null}}`, want: `{"a":{"b":"d"}}`},
+		{name: "arrays are not merged", target: `{"a":[{"b":"c"}]}`, patch: `{"a":[1]}`, want: `{"a":[1]}`},
+		{name: "object over a scalar", target: `{"e":null}`, patch: `{"a":1}`, want: `{"e":null,"a":1}`},
+		{name: "object over an array", target: `[1,2]`, patch: `{"a":"b","c":null}`, want: `{"a":"b"}`},
+		{name: "nested null is not kept", target: `{}`, patch: `{"a":{"bb":{"ccc":null}}}`, want: `{"a":{"bb":{}}}`},
+		{name: "key differing by case replaces", target: `{"Name":"a"}`, patch: `{"name":"b"}`, want: `{"Name":"b"}`},
+		{name: "key differing by case removes", target: `{"Name":"a","ID":"1"}`, patch: `{"NAME":null}`, want: `{"ID":"1"}`},
+		{name: "nested key differing by case", target: `{"Series":{"Name":"a"}}`, patch: `{"series":{"name":"b"}}`, want: `{"Series":{"Name":"b"}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var target, patch, want interface{}
+			mustUnmarshal(t, tt.target, &target)
+			mustUnmarshal(t, tt.patch, &patch)
+			mustUnmarshal(t, tt.want, &want)
+
+			if got := applyMergePatch(target, patch); !reflect.DeepEqual(got, want) {
+				t.Errorf("applyMergePatch(%s, %s) = %v, want %v", tt.target, tt.patch, got, want)
+			}
+		})
+	}
+}
+
+func TestPatchHasField(t *testing.T) {
+	patch := map[string]interface{}{"Authors": nil, "title": "a"}
+
+	tests := []struct {
+		field string
+		want  bool
+	}{
+		{field: "Authors", want: true},
+		{field: "authors", want: true},
+		{field: "Title", want: true},
+		{field: "Categories", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			if got := patchHasField(patch, tt.field); got != tt.want {
+				t.Errorf("patchHasField(%q) = %v, want %v", tt.field, got, tt.want)
+			}
+		})
+	}
+}
+
+func mustUnmarshal(t *testing.T, value string, dst interface{}) {
+	t.Helper()
+
+	if err := json.Unmarshal([]byte(value), dst); err != nil {
+		t.Fatalf("invalid JSON %s: %v", value, err)
+	}
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+type pageResponse struct {
+	Limit      int
+	Sort       string
+	NextCursor string
+	PrevCursor string
+	HasNext    bool
+	HasPrev    bool
+}
+
+// parsePageRequest reads the "limit", "cursor" and "sort" query parameters
+func parsePageRequest(c *gin.Context) (repository.PageRequest, error) {
+	var page repository.PageRequest
+
+	if limit := c.Query("limit"); limit != "" {
+		value, err := strconv.Atoi(limit)
+		if err != nil || value <= 0 {
+			return page, fmt.Errorf("limit must be a positive integer")
+		}
+		if value > repository.MaxPageLimit {
+			return page, fmt.Errorf("limit must be at most %d", repository.MaxPageLimit)
+		}
+		page.Limit = value
+	}
+
+	switch sort := repository.SortDirection(c.DefaultQuery("sort", string(repository.SortAsc))); sort {
+	case repository.SortAsc, repository.SortDesc:
+		page.Sort = sort
+	default:
+		return page, fmt.Errorf("sort must be either %q or %q", repository.SortAsc, repository.SortDesc)
+	}
+
+	page.Cursor = c.Query("cursor")
+
+	return page, nil
+}
+
+func formatPageResponse(info *repository.PageInfo) pageResponse {
+	return pageResponse{
+		Limit:      info.Limit,
+		Sort:       string(info.Sort),
+		NextCursor: info.NextCursor,
+		PrevCursor: info.PrevCursor,
+		HasNext:    info.HasNext,
+		HasPrev:    info.HasPrev,
+	}
+}
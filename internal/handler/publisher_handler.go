@@ -0,0 +1,252 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/domain"
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type PublisherHandler struct {
+	publisherService service.PublisherService
+}
+
+type publisherRequest struct {
+	Name string `binding:"required"`
+}
+
+type publisherResponse struct {
+	ID   string
+	Name string
+}
+
+func NewPublisherHandler(publisherService service.PublisherService) *PublisherHandler {
+	return &PublisherHandler{publisherService}
+}
+
+func (h *PublisherHandler) CreatePublisher(c *gin.Context) {
+	var request publisherRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondBindingError(c, err)
+		return
+	}
+
+	var publisher domain.Publisher
+	publisher.Name = request.Name
+
+	if err := h.publisherService.CreatePublisher(c.Request.Context(), &publisher); err != nil {
+		respondError(c, "CREATE_PUBLISHER_ERROR", "error while creating publisher", err)
+		return
+	}
+
+	c.JSON(
+		http.StatusCreated,
+		gin.H{
+			"data": gin.H{
+				"message": "Publisher created successfully",
+			},
+		},
+	)
+}
+
+func (h *PublisherHandler) FindPublisherByID(c *gin.Context) {
+	publisherID := c.Param("id")
+	if publisherID == "" {
+		respondProblem(
+			c,
+			http.StatusBadRequest,
+			"INVALID_REQUEST_PARAMETER",
+			"invalid request parameter",
+			"Publisher ID is required",
+		)
+		return
+	}
+
+	publisher, err := h.publisherService.FindPublisherByID(c.Request.Context(), publisherID)
+	if err != nil {
+		respondError(c, "FIND_PUBLISHER_BY_ID_ERROR", "error while finding publisher by ID", err)
+		return
+	}
+
+	setETag(c, publisher.Version)
+
+	c.JSON(
+		http.StatusOK,
+		gin.H{
+			"data": gin.H{
+				"publisher": formatPublisherResponse(publisher),
+			},
+		},
+	)
+}
+
+func (h *PublisherHandler) FindPublisherByName(c *gin.Context) {
+	publisherName := c.Param("name")
+	if publisherName == "" {
+		respondProblem(
+			c,
+			http.StatusBadRequest,
+			"INVALID_REQUEST_PARAMETER",
+			"invalid request parameter",
+			"Publisher name is required",
+		)
+		return
+	}
+
+	publisher, err := h.publisherService.FindPublisherByName(c.Request.Context(), publisherName)
+	if err != nil {
+		respondError(c, "FIND_PUBLISHER_BY_NAME_ERROR", "error while finding publisher by name", err)
+		return
+	}
+
+	setETag(c, publisher.Version)
+
+	c.JSON(
+		http.StatusOK,
+		gin.H{
+			"data": gin.H{
+				"publisher": formatPublisherResponse(publisher),
+			},
+		},
+	)
+}
+
+func (h *PublisherHandler) FindAllPublishers(c *gin.Context) {
+	page, err := parsePageRequest(c)
+	if err != nil {
+		respondProblem(
+			c,
+			http.StatusBadRequest,
+			"INVALID_REQUEST_PARAMETER",
+			"invalid request parameter",
+			"Error while parsing pagination: "+err.Error(),
+		)
+		return
+	}
+
+	publishers, pageInfo, err := h.publisherService.FindAllPublishers(c.Request.Context(), page)
+	if err != nil {
+		respondError(c, "FIND_ALL_PUBLISHERS_ERROR", "error while finding all publishers", err)
+		return
+	}
+
+	publishersResponse := []*publisherResponse{}
+	for _, publisher := range publishers {
+		publishersResponse = append(publishersResponse, formatPublisherResponse(publisher))
+	}
+
+	c.JSON(
+		http.StatusOK,
+		gin.H{
+			"data": gin.H{
+				"publishers": publishersResponse,
+			},
+			"page": formatPageResponse(pageInfo),
+		},
+	)
+}
+
+func (h *PublisherHandler) UpdatePublisher(c *gin.Context) {
+	publisherID := c.Param("id")
+	if publisherID == "" {
+		respondProblem(
+			c,
+			http.StatusBadRequest,
+			"INVALID_REQUEST_PARAMETER",
+			"invalid request parameter",
+			"Publisher ID is required",
+		)
+		return
+	}
+
+	var request publisherRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondBindingError(c, err)
+		return
+	}
+
+	h.updatePublisher(c, publisherID, &request)
+}
+
+// PatchPublisher applies a JSON merge patch to the publisher,
+// only the fields present on the patch are changed
+func (h *PublisherHandler) PatchPublisher(c *gin.Context) {
+	publisherID := c.Param("id")
+	if publisherID == "" {
+		respondProblem(
+			c,
+			http.StatusBadRequest,
+			"INVALID_REQUEST_PARAMETER",
+			"invalid request parameter",
+			"Publisher ID is required",
+		)
+		return
+	}
+
+	publisher, err := h.publisherService.FindPublisherByID(c.Request.Context(), publisherID)
+	if err != nil {
+		respondError(c, "FIND_PUBLISHER_BY_ID_ERROR", "error while finding publisher by ID", err)
+		return
+	}
+
+	var request publisherRequest
+	current := publisherRequest{Name: publisher.Name}
+	if _, ok := bindMergePatch(c, &current, &request); !ok {
+		return
+	}
+
+	h.updatePublisher(c, publisherID, &request)
+}
+
+func (h *PublisherHandler) updatePublisher(c *gin.Context, publisherID string, request *publisherRequest) {
+	version, ok := parseIfMatch(c)
+	if !ok {
+		return
+	}
+
+	var publisher domain.Publisher
+	publisher.ID = publisherID
+	publisher.Name = request.Name
+	publisher.Version = version
+
+	if err := h.publisherService.UpdatePublisher(c.Request.Context(), &publisher); err != nil {
+		respondError(c, "UPDATE_PUBLISHER_ERROR", "error while updating publisher", err)
+		return
+	}
+
+	setETag(c, publisher.Version)
+	c.JSON(
+		http.StatusNoContent,
+		gin.H{},
+	)
+}
+
+func (h *PublisherHandler) DeletePublisherByID(c *gin.Context) {
+	publisherID := c.Param("id")
+	if publisherID == "" {
+		respondProblem(
+			c,
+			http.StatusBadRequest,
+			"INVALID_REQUEST_PARAMETER",
+			"invalid request parameter",
+			"Publisher ID is required",
+		)
+		return
+	}
+
+	version, ok := parseIfMatch(c)
+	if !ok {
+		return
+	}
+
+	if err := h.publisherService.DeletePublisherByID(c.Request.Context(), publisherID, version); err != nil {
+		respondError(c, "DELETE_PUBLISHER_BY_ID_ERROR", "error while deleting publisher by ID", err)
+		return
+	}
+
+	c.JSON(
+		http.StatusNoContent,
+		gin.H{},
+	)
+}
@@ -0,0 +1,252 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/domain"
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type SeriesHandler struct {
+	seriesService service.SeriesService
+}
+
+type seriesRequest struct {
+	Name string `binding:"required"`
+}
+
+type seriesResponse struct {
+	ID   string
+	Name string
+}
+
+func NewSeriesHandler(seriesService service.SeriesService) *SeriesHandler {
+	return &SeriesHandler{seriesService}
+}
+
+func (h *SeriesHandler) CreateSeries(c *gin.Context) {
+	var request seriesRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondBindingError(c, err)
+		return
+	}
+
+	var series domain.Series
+	series.Name = request.Name
+
+	if err := h.seriesService.CreateSeries(c.Request.Context(), &series); err != nil {
+		respondError(c, "CREATE_SERIES_ERROR", "error while creating series", err)
+		return
+	}
+
+	c.JSON(
+		http.StatusCreated,
+		gin.H{
+			"data": gin.H{
+				"message": "Series created successfully",
+			},
+		},
+	)
+}
+
+func (h *SeriesHandler) FindSeriesByID(c *gin.Context) {
+	seriesID := c.Param("id")
+	if seriesID == "" {
+		respondProblem(
+			c,
+			http.StatusBadRequest,
+			"INVALID_REQUEST_PARAMETER",
+			"invalid request parameter",
+			"Series ID is required",
+		)
+		return
+	}
+
+	series, err := h.seriesService.FindSeriesByID(c.Request.Context(), seriesID)
+	if err != nil {
+		respondError(c, "FIND_SERIES_BY_ID_ERROR", "error while finding series by ID", err)
+		return
+	}
+
+	setETag(c, series.Version)
+
+	c.JSON(
+		http.StatusOK,
+		gin.H{
+			"data": gin.H{
+				"series": formatSeriesResponse(series),
+			},
+		},
+	)
+}
+
+func (h *SeriesHandler) FindSeriesByName(c *gin.Context) {
+	seriesName := c.Param("name")
+	if seriesName == "" {
+		respondProblem(
+			c,
+			http.StatusBadRequest,
+			"INVALID_REQUEST_PARAMETER",
+			"invalid request parameter",
+			"Series name is required",
+		)
+		return
+	}
+
+	series, err := h.seriesService.FindSeriesByName(c.Request.Context(), seriesName)
+	if err != nil {
+		respondError(c, "FIND_SERIES_BY_NAME_ERROR", "error while finding series by name", err)
+		return
+	}
+
+	setETag(c, series.Version)
+
+	c.JSON(
+		http.StatusOK,
+		gin.H{
+			"data": gin.H{
+				"series": formatSeriesResponse(series),
+			},
+		},
+	)
+}
+
+func (h *SeriesHandler) FindAllSeries(c *gin.Context) {
+	page, err := parsePageRequest(c)
+	if err != nil {
+		respondProblem(
+			c,
+			http.StatusBadRequest,
+			"INVALID_REQUEST_PARAMETER",
+			"invalid request parameter",
+			"Error while parsing pagination: "+err.Error(),
+		)
+		return
+	}
+
+	seriesList, pageInfo, err := h.seriesService.FindAllSeries(c.Request.Context(), page)
+	if err != nil {
+		respondError(c, "FIND_ALL_SERIES_ERROR", "error while finding all series", err)
+		return
+	}
+
+	seriesListResponse := []*seriesResponse{}
+	for _, series := range seriesList {
+		seriesListResponse = append(seriesListResponse, formatSeriesResponse(series))
+	}
+
+	c.JSON(
+		http.StatusOK,
+		gin.H{
+			"data": gin.H{
+				"series": seriesListResponse,
+			},
+			"page": formatPageResponse(pageInfo),
+		},
+	)
+}
+
+func (h *SeriesHandler) UpdateSeries(c *gin.Context) {
+	seriesID := c.Param("id")
+	if seriesID == "" {
+		respondProblem(
+			c,
+			http.StatusBadRequest,
+			"INVALID_REQUEST_PARAMETER",
+			"invalid request parameter",
+			"Series ID is required",
+		)
+		return
+	}
+
+	var request seriesRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondBindingError(c, err)
+		return
+	}
+
+	h.updateSeries(c, seriesID, &request)
+}
+
+// PatchSeries applies a JSON merge patch to the series,
+// only the fields present on the patch are changed
+func (h *SeriesHandler) PatchSeries(c *gin.Context) {
+	seriesID := c.Param("id")
+	if seriesID == "" {
+		respondProblem(
+			c,
+			http.StatusBadRequest,
+			"INVALID_REQUEST_PARAMETER",
+			"invalid request parameter",
+			"Series ID is required",
+		)
+		return
+	}
+
+	series, err := h.seriesService.FindSeriesByID(c.Request.Context(), seriesID)
+	if err != nil {
+		respondError(c, "FIND_SERIES_BY_ID_ERROR", "error while finding series by ID", err)
+		return
+	}
+
+	var request seriesRequest
+	current := seriesRequest{Name: series.Name}
+	if _, ok := bindMergePatch(c, &current, &request); !ok {
+		return
+	}
+
+	h.updateSeries(c, seriesID, &request)
+}
+
+func (h *SeriesHandler) updateSeries(c *gin.Context, seriesID string, request *seriesRequest) {
+	version, ok := parseIfMatch(c)
+	if !ok {
+		return
+	}
+
+	var series domain.Series
+	series.ID = seriesID
+	series.Name = request.Name
+	series.Version = version
+
+	if err := h.seriesService.UpdateSeries(c.Request.Context(), &series); err != nil {
+		respondError(c, "UPDATE_SERIES_ERROR", "error while updating series", err)
+		return
+	}
+
+	setETag(c, series.Version)
+	c.JSON(
+		http.StatusNoContent,
+		gin.H{},
+	)
+}
+
+func (h *SeriesHandler) DeleteSeriesByID(c *gin.Context) {
+	seriesID := c.Param("id")
+	if seriesID == "" {
+		respondProblem(
+			c,
+			http.StatusBadRequest,
+			"INVALID_REQUEST_PARAMETER",
+			"invalid request parameter",
+			"Series ID is required",
+		)
+		return
+	}
+
+	version, ok := parseIfMatch(c)
+	if !ok {
+		return
+	}
+
+	if err := h.seriesService.DeleteSeriesByID(c.Request.Context(), seriesID, version); err != nil {
+		respondError(c, "DELETE_SERIES_BY_ID_ERROR", "error while deleting series by ID", err)
+		return
+	}
+
+	c.JSON(
+		http.StatusNoContent,
+		gin.H{},
+	)
+}
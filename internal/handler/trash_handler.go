@@ -0,0 +1,250 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// TrashHandler lists, restores and purges the soft deleted
+// books, authors, categories, publishers and series
+type TrashHandler struct {
+	books      *BookHandler
+	authors    *AuthorHandler
+	categories *CategoryHandler
+	publishers *PublisherHandler
+	series     *SeriesHandler
+}
+
+func NewTrashHandler(
+	books *BookHandler,
+	authors *AuthorHandler,
+	categories *CategoryHandler,
+	publishers *PublisherHandler,
+	series *SeriesHandler,
+) *TrashHandler {
+	return &TrashHandler{books, authors, categories, publishers, series}
+}
+
+func (h *TrashHandler) FindDeletedBooks(c *gin.Context) {
+	page, ok := h.parsePage(c)
+	if !ok {
+		return
+	}
+
+	books, pageInfo, err := h.books.bookService.FindDeletedBooks(c.Request.Context(), page)
+	if err != nil {
+		respondError(c, "FIND_DELETED_BOOKS_ERROR", "error while finding deleted books", err)
+		return
+	}
+
+	booksResponse := []bookResponse{}
+	for _, book := range books {
+		booksResponse = append(booksResponse, h.books.formatBookResponse(book))
+	}
+
+	c.JSON(
+		http.StatusOK,
+		gin.H{
+			"data": gin.H{
+				"books": booksResponse,
+			},
+			"page": formatPageResponse(pageInfo),
+		},
+	)
+}
+
+func (h *TrashHandler) FindDeletedAuthors(c *gin.Context) {
+	page, ok := h.parsePage(c)
+	if !ok {
+		return
+	}
+
+	authors, pageInfo, err := h.authors.authorService.FindDeletedAuthors(c.Request.Context(), page)
+	if err != nil {
+		respondError(c, "FIND_DELETED_AUTHORS_ERROR", "error while finding deleted authors", err)
+		return
+	}
+
+	authorsResponse := []*authorResponse{}
+	for _, author := range authors {
+		authorsResponse = append(authorsResponse, h.authors.formatAuthorResponse(author))
+	}
+
+	c.JSON(
+		http.StatusOK,
+		gin.H{
+			"data": gin.H{
+				"authors": authorsResponse,
+			},
+			"page": formatPageResponse(pageInfo),
+		},
+	)
+}
+
+func (h *TrashHandler) FindDeletedCategories(c *gin.Context) {
+	page, ok := h.parsePage(c)
+	if !ok {
+		return
+	}
+
+	categories, pageInfo, err := h.categories.categoryService.FindDeletedCategories(c.Request.Context(), page)
+	if err != nil {
+		respondError(c, "FIND_DELETED_CATEGORIES_ERROR", "error while finding deleted categories", err)
+		return
+	}
+
+	categoriesResponse := []categoryResponse{}
+	for _, category := range categories {
+		categoriesResponse = append(categoriesResponse, h.categories.formatCategoryDataReturn(category))
+	}
+
+	c.JSON(
+		http.StatusOK,
+		gin.H{
+			"data": gin.H{
+				"categories": categoriesResponse,
+			},
+			"page": formatPageResponse(pageInfo),
+		},
+	)
+}
+
+func (h *TrashHandler) FindDeletedPublishers(c *gin.Context) {
+	page, ok := h.parsePage(c)
+	if !ok {
+		return
+	}
+
+	publishers, pageInfo, err := h.publishers.publisherService.FindDeletedPublishers(c.Request.Context(), page)
+	if err != nil {
+		respondError(c, "FIND_DELETED_PUBLISHERS_ERROR", "error while finding deleted publishers", err)
+		return
+	}
+
+	publishersResponse := []*publisherResponse{}
+	for _, publisher := range publishers {
+		publishersResponse = append(publishersResponse, formatPublisherResponse(publisher))
+	}
+
+	c.JSON(
+		http.StatusOK,
+		gin.H{
+			"data": gin.H{
+				"publishers": publishersResponse,
+			},
+			"page": formatPageResponse(pageInfo),
+		},
+	)
+}
+
+func (h *TrashHandler) FindDeletedSeries(c *gin.Context) {
+	page, ok := h.parsePage(c)
+	if !ok {
+		return
+	}
+
+	seriesList, pageInfo, err := h.series.seriesService.FindDeletedSeries(c.Request.Context(), page)
+	if err != nil {
+		respondError(c, "FIND_DELETED_SERIES_ERROR", "error while finding deleted series", err)
+		return
+	}
+
+	seriesListResponse := []*seriesResponse{}
+	for _, series := range seriesList {
+		seriesListResponse = append(seriesListResponse, formatSeriesResponse(series))
+	}
+
+	c.JSON(
+		http.StatusOK,
+		gin.H{
+			"data": gin.H{
+				"series": seriesListResponse,
+			},
+			"page": formatPageResponse(pageInfo),
+		},
+	)
+}
+
+func (h *TrashHandler) RestoreBook(c *gin.Context) {
+	h.apply(c, "RESTORE_BOOK_ERROR", "error while restoring book", h.books.bookService.RestoreBookByID)
+}
+
+func (h *TrashHandler) RestoreAuthor(c *gin.Context) {
+	h.apply(c, "RESTORE_AUTHOR_ERROR", "error while restoring author", h.authors.authorService.RestoreAuthorByID)
+}
+
+func (h *TrashHandler) RestoreCategory(c *gin.Context) {
+	h.apply(c, "RESTORE_CATEGORY_ERROR", "error while restoring category", h.categories.categoryService.RestoreCategoryByID)
+}
+
+func (h *TrashHandler) RestorePublisher(c *gin.Context) {
+	h.apply(c, "RESTORE_PUBLISHER_ERROR", "error while restoring publisher", h.publishers.publisherService.RestorePublisherByID)
+}
+
+func (h *TrashHandler) RestoreSeries(c *gin.Context) {
+	h.apply(c, "RESTORE_SERIES_ERROR", "error while restoring series", h.series.seriesService.RestoreSeriesByID)
+}
+
+func (h *TrashHandler) PurgeBook(c *gin.Context) {
+	h.apply(c, "PURGE_BOOK_ERROR", "error while purging book", h.books.bookService.PurgeBookByID)
+}
+
+func (h *TrashHandler) PurgeAuthor(c *gin.Context) {
+	h.apply(c, "PURGE_AUTHOR_ERROR", "error while purging author", h.authors.authorService.PurgeAuthorByID)
+}
+
+func (h *TrashHandler) PurgeCategory(c *gin.Context) {
+	h.apply(c, "PURGE_CATEGORY_ERROR", "error while purging category", h.categories.categoryService.PurgeCategoryByID)
+}
+
+func (h *TrashHandler) PurgePublisher(c *gin.Context) {
+	h.apply(c, "PURGE_PUBLISHER_ERROR", "error while purging publisher", h.publishers.publisherService.PurgePublisherByID)
+}
+
+func (h *TrashHandler) PurgeSeries(c *gin.Context) {
+	h.apply(c, "PURGE_SERIES_ERROR", "error while purging series", h.series.seriesService.PurgeSeriesByID)
+}
+
+// apply runs a restore or purge operation over the record on the path
+func (h *TrashHandler) apply(c *gin.Context, code, message string, operation func(ctx context.Context, id string) error) {
+	id := c.Param("id")
+	if id == "" {
+		respondProblem(
+			c,
+			http.StatusBadRequest,
+			"INVALID_REQUEST_PARAMETER",
+			"invalid request parameter",
+			"ID is required",
+		)
+		return
+	}
+
+	if err := operation(c.Request.Context(), id); err != nil {
+		respondError(c, code, message, err)
+		return
+	}
+
+	c.JSON(
+		http.StatusNoContent,
+		gin.H{},
+	)
+}
+
+func (h *TrashHandler) parsePage(c *gin.Context) (repository.PageRequest, bool) {
+	page, err := parsePageRequest(c)
+	if err != nil {
+		respondProblem(
+			c,
+			http.StatusBadRequest,
+			"INVALID_REQUEST_PARAMETER",
+			"invalid request parameter",
+			"Error while parsing pagination: "+err.Error(),
+		)
+		return page, false
+	}
+
+	return page, true
+}
@@ -0,0 +1,118 @@
+// Package isbn validates ISBN-10 and ISBN-13 codes and converts
+// between them, the books store the ISBN-13 form
+package isbn
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrInvalidLength   = errors.New("must have 10 or 13 digits")
+	ErrInvalidChar     = errors.New("must only have digits, hyphens or spaces (and a final X on ISBN-10)")
+	ErrInvalidChecksum = errors.New("has an invalid check digit")
+)
+
+// bookland is the prefix of the ISBN-13 codes converted from ISBN-10
+const bookland = "978"
+
+// Normalize validates an ISBN-10 or ISBN-13, written with or without
+// hyphens and spaces, and returns it as a plain ISBN-13
+func Normalize(value string) (string, error) {
+	code := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(value))
+
+	switch len(code) {
+	case 10:
+		if err := validateISBN10(code); err != nil {
+			return "", err
+		}
+
+		return ToISBN13(code), nil
+	case 13:
+		if err := validateISBN13(code); err != nil {
+			return "", err
+		}
+
+		return code, nil
+	default:
+		return "", ErrInvalidLength
+	}
+}
+
+// ToISBN13 converts a valid plain ISBN-10 to the ISBN-13 form
+func ToISBN13(isbn10 string) string {
+	code := bookland + isbn10[:9]
+	return code + string(isbn13CheckDigit(code))
+}
+
+// ToISBN10 converts a valid plain ISBN-13 to the ISBN-10 form,
+// only the codes starting with 978 have one
+func ToISBN10(isbn13 string) (string, bool) {
+	if len(isbn13) != 13 || !strings.HasPrefix(isbn13, bookland) {
+		return "", false
+	}
+
+	code := isbn13[3:12]
+	return code + string(isbn10CheckDigit(code)), true
+}
+
+func validateISBN10(code string) error {
+	for i, char := range code {
+		if !isDigit(char) && !(i == 9 && char == 'X') {
+			return ErrInvalidChar
+		}
+	}
+
+	if isbn10CheckDigit(code[:9]) != code[9] {
+		return ErrInvalidChecksum
+	}
+
+	return nil
+}
+
+func validateISBN13(code string) error {
+	for _, char := range code {
+		if !isDigit(char) {
+			return ErrInvalidChar
+		}
+	}
+
+	if isbn13CheckDigit(code[:12]) != code[12] {
+		return ErrInvalidChecksum
+	}
+
+	return nil
+}
+
+// isbn10CheckDigit weights the 9 digits from 10 down to 2 (modulo 11)
+func isbn10CheckDigit(digits string) byte {
+	sum := 0
+	for i := 0; i < 9; i++ {
+		sum += int(digits[i]-'0') * (10 - i)
+	}
+
+	check := (11 - sum%11) % 11
+	if check == 10 {
+		return 'X'
+	}
+
+	return byte('0' + check)
+}
+
+// isbn13CheckDigit weights the 12 digits alternately by 1 and 3 (modulo 10)
+func isbn13CheckDigit(digits string) byte {
+	sum := 0
+	for i := 0; i < 12; i++ {
+		weight := 1
+		if i%2 == 1 {
+			weight = 3
+		}
+		sum += int(digits[i]-'0') * weight
+	}
+
+	return byte('0' + (10-sum%10)%10)
+}
+
+func isDigit(char rune) bool {
+	return char >= '0' && char <= '9'
+}
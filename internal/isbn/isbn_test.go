@@ -0,0 +1,86 @@
+package isbn
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+		err   error
+	}{
+		{name: "plain ISBN-13", value: "9780306406157", want: "9780306406157"},
+		{name: "ISBN-13 with hyphens", value: "978-0-306-40615-7", want: "9780306406157"},
+		{name: "ISBN-13 with spaces", value: "978 0 306 40615 7", want: "9780306406157"},
+		{name: "ISBN-13 with 979 prefix", value: "979-10-90636-07-1", want: "9791090636071"},
+		{name: "plain ISBN-10", value: "0306406152", want: "9780306406157"},
+		{name: "ISBN-10 with hyphens", value: "0-306-40615-2", want: "9780306406157"},
+		{name: "ISBN-10 with X check digit", value: "0-8044-2957-X", want: "9780804429573"},
+		{name: "ISBN-10 with lower case x check digit", value: "080442957x", want: "9780804429573"},
+		{name: "empty", value: "", err: ErrInvalidLength},
+		{name: "too short", value: "978030640615", err: ErrInvalidLength},
+		{name: "too long", value: "97803064061570", err: ErrInvalidLength},
+		{name: "letter on ISBN-13", value: "978030640615A", err: ErrInvalidChar},
+		{name: "X on ISBN-13", value: "978030640615X", err: ErrInvalidChar},
+		{name: "X before the check digit of ISBN-10", value: "03064061X2", err: ErrInvalidChar},
+		{name: "wrong ISBN-13 check digit", value: "9780306406158", err: ErrInvalidChecksum},
+		{name: "wrong ISBN-10 check digit", value: "0306406153", err: ErrInvalidChecksum},
+		{name: "digit instead of X on ISBN-10", value: "0804429570", err: ErrInvalidChecksum},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Normalize(tt.value)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Normalize(%q) error = %v, want %v", tt.value, err, tt.err)
+			}
+			if got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToISBN13(t *testing.T) {
+	tests := []struct {
+		isbn10 string
+		want   string
+	}{
+		{isbn10: "0306406152", want: "9780306406157"},
+		{isbn10: "080442957X", want: "9780804429573"},
+		{isbn10: "8535902775", want: "9788535902778"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.isbn10, func(t *testing.T) {
+			if got := ToISBN13(tt.isbn10); got != tt.want {
+				t.Errorf("ToISBN13(%q) = %q, want %q", tt.isbn10, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToISBN10(t *testing.T) {
+	tests := []struct {
+		isbn13 string
+		want   string
+		ok     bool
+	}{
+		{isbn13: "9780306406157", want: "0306406152", ok: true},
+		{isbn13: "9780804429573", want: "080442957X", ok: true},
+		{isbn13: "9791090636071", ok: false},
+		{isbn13: "978030640615", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.isbn13, func(t *testing.T) {
+			got, ok := ToISBN10(tt.isbn13)
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("ToISBN10(%q) = %q, %v, want %q, %v", tt.isbn13, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
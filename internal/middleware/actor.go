@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// ActorHeader tells who the client claims to be. Nothing authenticates it,
+// so it is only recorded as the claimed actor on the audit log, apart from
+// the actor verified through the administrator token
+const ActorHeader = "X-Actor"
+
+// maxActorLength is the size, in characters, of the audit_logs.claimed_actor column
+const maxActorLength = 100
+
+// Actor stores who is making the request in the request context, so the
+// services can record it on the audit log. The requests sending the
+// administrator token are made by the administrator, the others are
+// anonymous, and the X-Actor header is kept as the claimed actor
+func Actor(adminToken string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		if sent, found := bearerToken(c); found && isAdminToken(sent, adminToken) {
+			ctx = service.WithActor(ctx, service.AdminActor)
+		}
+
+		// The header may carry bytes that are not valid UTF-8, which the
+		// database would refuse, and it is cut on a character boundary
+		claimed := strings.TrimSpace(strings.ToValidUTF8(c.GetHeader(ActorHeader), "\uFFFD"))
+		if runes := []rune(claimed); len(runes) > maxActorLength {
+			claimed = string(runes[:maxActorLength])
+		}
+
+		if claimed != "" {
+			ctx = service.WithClaimedActor(ctx, claimed)
+		}
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/problem"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireAdmin only lets through the requests sending the administrator
+// token as "Authorization: Bearer <token>". When no token is configured
+// every request is refused
+func RequireAdmin(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sent, found := bearerToken(c)
+		if !found {
+			problem.Respond(c, http.StatusUnauthorized, "UNAUTHORIZED", "unauthorized", "An administrator token is required")
+			return
+		}
+
+		if !isAdminToken(sent, token) {
+			problem.Respond(c, http.StatusForbidden, "FORBIDDEN", "forbidden", "Only administrators can access this resource")
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// bearerToken returns the token sent as "Authorization: Bearer <token>"
+func bearerToken(c *gin.Context) (string, bool) {
+	sent, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	return sent, found && sent != ""
+}
+
+// isAdminToken compares the tokens in constant time,
+// no token matches when none is configured
+func isAdminToken(sent, token string) bool {
+	return token != "" && subtle.ConstantTimeCompare([]byte(sent), []byte(token)) == 1
+}
@@ -0,0 +1,43 @@
+package problem
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const ContentType = "application/problem+json"
+
+// Problem is the RFC 7807 body sent on every error response
+type Problem struct {
+	Type       string      `json:"type"`
+	Title      string      `json:"title"`
+	Status     int         `json:"status"`
+	Detail     string      `json:"detail,omitempty"`
+	Instance   string      `json:"instance,omitempty"`
+	Code       string      `json:"code"`
+	Violations []Violation `json:"violations,omitempty"`
+}
+
+type Violation struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Respond aborts the request with the problem+json body, it is shared by
+// the handlers and the middlewares so every error has the same shape
+func Respond(c *gin.Context, status int, code, title, detail string, violations ...Violation) {
+	c.Header("Content-Type", ContentType)
+	c.AbortWithStatusJSON(
+		status,
+		Problem{
+			Type:       "/problems/" + strings.ToLower(strings.ReplaceAll(code, "_", "-")),
+			Title:      title,
+			Status:     status,
+			Detail:     detail,
+			Instance:   c.Request.URL.Path,
+			Code:       code,
+			Violations: violations,
+		},
+	)
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// AuditLogFilter narrows the audit log entries, the empty fields are ignored
+type AuditLogFilter struct {
+	EntityType   string
+	EntityID     string
+	Actor        string
+	ClaimedActor string
+	From         *time.Time
+	To           *time.Time
+}
+
+type AuditLogRepository interface {
+	Create(ctx context.Context, auditLog *domain.AuditLog) error
+	FindAll(ctx context.Context, filter AuditLogFilter, page PageRequest) ([]*domain.AuditLog, *PageInfo, error)
+}
+
+type gormAuditLogRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditLogRepository(db *gorm.DB) AuditLogRepository {
+	return &gormAuditLogRepository{db}
+}
+
+func (r *gormAuditLogRepository) Create(ctx context.Context, auditLog *domain.AuditLog) error {
+	return translateError(r.db.WithContext(ctx).Create(auditLog).Error)
+}
+
+func (r *gormAuditLogRepository) FindAll(ctx context.Context, filter AuditLogFilter, page PageRequest) ([]*domain.AuditLog, *PageInfo, error) {
+	query := r.db.WithContext(ctx).Model(&domain.AuditLog{})
+
+	if filter.EntityType != "" {
+		query = query.Where("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID != "" {
+		query = query.Where("entity_id = ?", filter.EntityID)
+	}
+	if filter.Actor != "" {
+		query = query.Where("actor = ?", filter.Actor)
+	}
+	if filter.ClaimedActor != "" {
+		query = query.Where("claimed_actor = ?", filter.ClaimedActor)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+
+	return paginate(query, page, func(auditLog *domain.AuditLog) string {
+		return auditLog.ID
+	})
+}
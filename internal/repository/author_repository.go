@@ -1,18 +1,32 @@
 package repository
 
 import (
+	"context"
+	"errors"
+	"time"
+
 	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/domain"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type AuthorRepository interface {
-	Create(author *domain.Author) error
-	FindByID(id string) (*domain.Author, error)
-	FindByName(name string) (*domain.Author, error)
-	FindAll() ([]*domain.Author, error)
-	Update(author *domain.Author) error
-	Delete(id string) error
+	Create(ctx context.Context, author *domain.Author) error
+	FindByID(ctx context.Context, id string) (*domain.Author, error)
+	FindByName(ctx context.Context, name string) (*domain.Author, error)
+	FindAll(ctx context.Context, page PageRequest) ([]*domain.Author, *PageInfo, error)
+	Update(ctx context.Context, author *domain.Author) error
+	Delete(ctx context.Context, id string, version int64) error
+	FindDeleted(ctx context.Context, page PageRequest) ([]*domain.Author, *PageInfo, error)
+	Restore(ctx context.Context, id string) error
+	Purge(ctx context.Context, id string) ([]ReleasedReference, error)
+	PurgeDeletedBefore(ctx context.Context, cutoff time.Time, batchSize int, onPurged PurgedFunc) (int64, error)
+	CreateAlias(ctx context.Context, alias *domain.AuthorAlias) error
+	FindAliases(ctx context.Context, authorID string) ([]*domain.AuthorAlias, error)
+	FindAliasByName(ctx context.Context, name string) (*domain.AuthorAlias, error)
+	DeleteAlias(ctx context.Context, authorID, aliasID string) error
+	Merge(ctx context.Context, sourceID, targetID string, version int64) error
 }
 
 type gormAuthorRepository struct {
@@ -23,44 +37,155 @@ func NewAuthorRepository(db *gorm.DB) AuthorRepository {
 	return &gormAuthorRepository{db}
 }
 
-func (r *gormAuthorRepository) Create(author *domain.Author) error {
-	return r.db.Create(author).Error
+func (r *gormAuthorRepository) Create(ctx context.Context, author *domain.Author) error {
+	return translateError(r.db.WithContext(ctx).Create(author).Error)
 }
 
-func (r *gormAuthorRepository) FindByID(id string) (*domain.Author, error) {
+// FindByID finds the author by its ID or, when the author
+// was merged into another one, finds the author it was merged into
+func (r *gormAuthorRepository) FindByID(ctx context.Context, id string) (*domain.Author, error) {
 	var author domain.Author
-	if err := r.db.
-		First(&author, id).Error; err != nil {
-		return nil, err
+	err := r.db.WithContext(ctx).
+		First(&author, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = r.db.WithContext(ctx).
+			Joins("JOIN author_redirects ON author_redirects.author_id = authors.id").
+			First(&author, "author_redirects.old_id = ?", id).Error
+	}
+	if err != nil {
+		return nil, translateError(err)
 	}
 
 	return &author, nil
 }
 
-func (r *gormAuthorRepository) FindByName(name string) (*domain.Author, error) {
+// FindByName finds the author by its name or, when no author has
+// that name, by one of its aliases. The case, the accents and the spaces are ignored
+func (r *gormAuthorRepository) FindByName(ctx context.Context, name string) (*domain.Author, error) {
 	var author domain.Author
-	if err := r.db.
-		First(&author, "name = ?", name).Error; err != nil {
-		return nil, err
+	err := r.db.WithContext(ctx).
+		First(&author, "authors.name_key = normalize_name(?)", name).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = r.db.WithContext(ctx).
+			Joins("JOIN author_aliases ON author_aliases.author_id = authors.id").
+			First(&author, "author_aliases.name_key = normalize_name(?)", name).Error
+	}
+	if err != nil {
+		return nil, translateError(err)
 	}
 
 	return &author, nil
 }
 
-func (r *gormAuthorRepository) FindAll() ([]*domain.Author, error) {
-	var authors []*domain.Author
-	if err := r.db.
-		Find(&authors).Error; err != nil {
-		return nil, err
+func (r *gormAuthorRepository) FindAll(ctx context.Context, page PageRequest) ([]*domain.Author, *PageInfo, error) {
+	return paginate(r.db.WithContext(ctx).Model(&domain.Author{}), page, func(author *domain.Author) string {
+		return author.ID
+	})
+}
+
+func (r *gormAuthorRepository) Update(ctx context.Context, author *domain.Author) error {
+	return updateVersioned(r.db.WithContext(ctx), author, &author.Base)
+}
+
+func (r *gormAuthorRepository) Delete(ctx context.Context, id string, version int64) error {
+	return deleteVersioned(r.db.WithContext(ctx), &domain.Author{}, id, version)
+}
+
+func (r *gormAuthorRepository) FindDeleted(ctx context.Context, page PageRequest) ([]*domain.Author, *PageInfo, error) {
+	return findDeleted(r.db.WithContext(ctx).Model(&domain.Author{}), page, func(author *domain.Author) string {
+		return author.ID
+	})
+}
+
+func (r *gormAuthorRepository) Restore(ctx context.Context, id string) error {
+	if err := checkNotMerged(r.db.WithContext(ctx), authorRedirects, id); err != nil {
+		return err
 	}
 
-	return authors, nil
+	return restoreDeleted(r.db.WithContext(ctx), &domain.Author{}, id)
+}
+
+func (r *gormAuthorRepository) Purge(ctx context.Context, id string) ([]ReleasedReference, error) {
+	return purgeDeleted(r.db.WithContext(ctx), &domain.Author{}, id, authorReferences)
+}
+
+func (r *gormAuthorRepository) PurgeDeletedBefore(ctx context.Context, cutoff time.Time, batchSize int, onPurged PurgedFunc) (int64, error) {
+	return purgeExpired(r.db.WithContext(ctx), &domain.Author{}, cutoff, batchSize, authorReferences, onPurged)
 }
 
-func (r *gormAuthorRepository) Update(author *domain.Author) error {
-	return r.db.Save(author).Error
+func (r *gormAuthorRepository) CreateAlias(ctx context.Context, alias *domain.AuthorAlias) error {
+	return translateError(r.db.WithContext(ctx).Create(alias).Error)
 }
 
-func (r *gormAuthorRepository) Delete(id string) error {
-	return r.db.Delete(&domain.Author{}, id).Error
+func (r *gormAuthorRepository) FindAliases(ctx context.Context, authorID string) ([]*domain.AuthorAlias, error) {
+	var aliases []*domain.AuthorAlias
+	if err := r.db.WithContext(ctx).
+		Where("author_id = ?", authorID).
+		Order("name").
+		Find(&aliases).Error; err != nil {
+		return nil, translateError(err)
+	}
+
+	return aliases, nil
+}
+
+// FindAliasByName finds the alias by its name, ignoring the case, the accents and the spaces
+func (r *gormAuthorRepository) FindAliasByName(ctx context.Context, name string) (*domain.AuthorAlias, error) {
+	var alias domain.AuthorAlias
+	if err := r.db.WithContext(ctx).
+		First(&alias, "name_key = normalize_name(?)", name).Error; err != nil {
+		return nil, translateError(err)
+	}
+
+	return &alias, nil
+}
+
+func (r *gormAuthorRepository) DeleteAlias(ctx context.Context, authorID, aliasID string) error {
+	result := r.db.WithContext(ctx).
+		Where("author_id = ?", authorID).
+		Delete(&domain.AuthorAlias{}, "id = ?", aliasID)
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return translateError(gorm.ErrRecordNotFound)
+	}
+
+	return nil
+}
+
+// Merge moves the books and aliases of the source author to the target
+// author and soft deletes the source, its ID keeps resolving to the target.
+// The name of the source becomes an alias of the target, so the next
+// lookups by that name find the target instead of creating the duplicate again
+func (r *gormAuthorRepository) Merge(ctx context.Context, sourceID, targetID string, version int64) error {
+	return translateError(r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := mergeInto(tx, &domain.Author{}, sourceID, targetID, version, authorMerges, authorRedirects); err != nil {
+			return err
+		}
+
+		var source domain.Author
+		if err := tx.
+			Unscoped().
+			First(&source, "id = ?", sourceID).Error; err != nil {
+			return err
+		}
+
+		// The names differing only on the case, the accents or the spaces already match the target
+		var sameName int64
+		if err := tx.
+			Model(&domain.Author{}).
+			Where("id = ? AND name_key = normalize_name(?)", targetID, source.Name).
+			Count(&sameName).Error; err != nil {
+			return err
+		}
+		if sameName > 0 {
+			return nil
+		}
+
+		// The target may already have the name as an alias
+		return tx.
+			Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&domain.AuthorAlias{AuthorID: targetID, Name: source.Name}).Error
+	}))
 }
@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type FilterMatch string
+
+const (
+	// FilterMatchAll keeps the books related to every given author/category
+	FilterMatchAll FilterMatch = "all"
+	// FilterMatchAny keeps the books related to at least one given author/category
+	FilterMatchAny FilterMatch = "any"
+)
+
+// BookFilter narrows the books returned by the book listing.
+// The authors and the categories are matched following the Match
+// semantics, while the different criteria are always combined with AND.
+// A book has a single publisher, so the publishers always match as "any".
+// With IncludeSubcategories a category also matches the books of its descendants.
+// The creation dates form the half-open range [CreatedFrom, CreatedTo)
+type BookFilter struct {
+	AuthorIDs            []string
+	AuthorNames          []string
+	CategoryIDs          []string
+	CategoryNames        []string
+	IncludeSubcategories bool
+	PublisherIDs         []string
+	PublisherNames       []string
+	Match                FilterMatch
+	CreatedFrom          *time.Time
+	CreatedTo            *time.Time
+}
+
+// nameMatch compares the names ignoring the case, the accents and the spaces
+const nameMatch = "%[1]s.name_key = normalize_name(?)"
+
+// associationFilter matches the books through one of the join tables
+type associationFilter struct {
+	joinTable   string
+	foreignKey  string
+	table       string
+	ids         []string
+	names       []string
+	descendants bool // the table is a tree linked by parent_id
+	redirects   redirectTable
+}
+
+func (f BookFilter) apply(query *gorm.DB) *gorm.DB {
+	authors := associationFilter{
+		joinTable:  "book_authors",
+		foreignKey: "author_id",
+		table:      "authors",
+		ids:        f.AuthorIDs,
+		names:      f.AuthorNames,
+		redirects:  authorRedirects,
+	}
+	categories := associationFilter{
+		joinTable:   "book_categories",
+		foreignKey:  "category_id",
+		table:       "categories",
+		ids:         f.CategoryIDs,
+		names:       f.CategoryNames,
+		descendants: f.IncludeSubcategories,
+		redirects:   categoryRedirects,
+	}
+
+	query = authors.apply(query, f.Match)
+	query = categories.apply(query, f.Match)
+	query = f.applyPublishers(query)
+
+	if f.CreatedFrom != nil {
+		query = query.Where("books.created_at >= ?", *f.CreatedFrom)
+	}
+	if f.CreatedTo != nil {
+		query = query.Where("books.created_at < ?", *f.CreatedTo)
+	}
+
+	return query
+}
+
+func (f BookFilter) applyPublishers(query *gorm.DB) *gorm.DB {
+	if len(f.PublisherIDs) == 0 && len(f.PublisherNames) == 0 {
+		return query
+	}
+
+	var conditions []string
+	var args []interface{}
+
+	if len(f.PublisherIDs) > 0 {
+		conditions = append(conditions, "publishers.id IN ?")
+		args = append(args, f.PublisherIDs)
+	}
+	for _, name := range f.PublisherNames {
+		conditions = append(conditions, fmt.Sprintf(nameMatch, "publishers"))
+		args = append(args, name)
+	}
+
+	// Soft deleted publishers must not match
+	return query.Where(
+		"EXISTS (SELECT 1 FROM publishers WHERE publishers.id = books.publisher_id "+
+			"AND publishers.deleted_at IS NULL AND ("+strings.Join(conditions, " OR ")+"))",
+		args...,
+	)
+}
+
+func (a associationFilter) apply(query *gorm.DB, match FilterMatch) *gorm.DB {
+	if len(a.ids) == 0 && len(a.names) == 0 {
+		return query
+	}
+
+	// Soft deleted authors and categories must not match
+	exists := fmt.Sprintf(
+		"EXISTS (SELECT 1 FROM %[1]s JOIN %[3]s ON %[3]s.id = %[1]s.%[2]s AND %[3]s.deleted_at IS NULL "+
+			"WHERE %[1]s.book_id = books.id AND (%%s))",
+		a.joinTable, a.foreignKey, a.table,
+	)
+
+	if match == FilterMatchAny {
+		var conditions []string
+		var args []interface{}
+
+		if len(a.ids) > 0 {
+			conditions = append(conditions, a.match(a.idMatch("IN")))
+			args = append(args, a.ids, a.ids)
+		}
+		for _, name := range a.names {
+			conditions = append(conditions, a.match(nameMatch))
+			args = append(args, name)
+		}
+
+		return query.Where(fmt.Sprintf(exists, strings.Join(conditions, " OR ")), args...)
+	}
+
+	// Every ID and name needs its own relation to the book
+	for _, id := range a.ids {
+		query = query.Where(fmt.Sprintf(exists, a.match(a.idMatch("="))), id, id)
+	}
+	for _, name := range a.names {
+		query = query.Where(fmt.Sprintf(exists, a.match(nameMatch)), name)
+	}
+
+	return query
+}
+
+// idMatch compares the IDs using the operator, the ID of a merged record
+// also matches the record it was merged into. The value is given twice
+func (a associationFilter) idMatch(operator string) string {
+	return fmt.Sprintf(
+		"(%%[1]s.id %[1]s ? OR %%[1]s.id IN (SELECT %[2]s FROM %[3]s WHERE old_id %[1]s ?))",
+		operator, a.redirects.column, a.redirects.table,
+	)
+}
+
+// match builds the condition applying the comparison to the table, the
+// comparison refers to the table as %[1]s. With descendants the related row may
+// also be anywhere below the matched ones, the recursive query uses UNION so a
+// cycle on parent_id cannot loop forever
+func (a associationFilter) match(comparison string) string {
+	if !a.descendants {
+		return fmt.Sprintf(comparison, a.table)
+	}
+
+	return fmt.Sprintf(
+		"%[1]s.id IN (WITH RECURSIVE subtree AS ("+
+			"SELECT root.id FROM %[1]s root WHERE root.deleted_at IS NULL AND %[2]s "+
+			"UNION SELECT child.id FROM %[1]s child JOIN subtree ON child.parent_id = subtree.id "+
+			"WHERE child.deleted_at IS NULL) SELECT id FROM subtree)",
+		a.table, fmt.Sprintf(comparison, "root"),
+	)
+}
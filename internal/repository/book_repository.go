@@ -1,20 +1,50 @@
 package repository
 
 import (
+	"context"
+	"fmt"
+	"time"
+
 	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/domain"
 
 	"gorm.io/gorm"
 )
 
 type BookRepository interface {
-	Create(book *domain.Book) error
-	FindByID(id string) (*domain.Book, error)
-	FindByTitle(title string) (*domain.Book, error)
-	FindAll() ([]*domain.Book, error)
-	Update(book *domain.Book) error
-	Delete(id string) error
+	Create(ctx context.Context, book *domain.Book) error
+	FindByID(ctx context.Context, id string) (*domain.Book, error)
+	FindByTitle(ctx context.Context, title string) (*domain.Book, error)
+	FindByISBN(ctx context.Context, isbn string) (*domain.Book, error)
+	FindBySeries(ctx context.Context, seriesID string) ([]*domain.Book, error)
+	FindBySeriesPosition(ctx context.Context, seriesID string, position int) (*domain.Book, error)
+	FindVolumeNeighbours(ctx context.Context, book *domain.Book) (previous, next *domain.Book, err error)
+	FindAll(ctx context.Context, filter BookFilter, page PageRequest) ([]*domain.Book, *PageInfo, error)
+	FindInBatches(ctx context.Context, filter BookFilter, batchSize int, fn func(books []*domain.Book) error) error
+	Search(ctx context.Context, term string, limit, offset int) ([]*BookSearchResult, error)
+	Update(ctx context.Context, book *domain.Book) error
+	UpdateAuthors(ctx context.Context, book *domain.Book, authors []domain.Author, mode AssociationMode) error
+	UpdateAuthorCredits(ctx context.Context, bookID string, credits []domain.BookAuthor) error
+	UpdateCategories(ctx context.Context, book *domain.Book, categories []domain.Category, mode AssociationMode) error
+	Delete(ctx context.Context, id string, version int64) error
+	FindDeleted(ctx context.Context, page PageRequest) ([]*domain.Book, *PageInfo, error)
+	Restore(ctx context.Context, id string) error
+	Purge(ctx context.Context, id string) ([]ReleasedReference, error)
+	PurgeDeletedBefore(ctx context.Context, cutoff time.Time, batchSize int, onPurged PurgedFunc) (int64, error)
 }
 
+// AssociationMode tells how the authors or categories given
+// on an update are applied to the ones already linked to a book
+type AssociationMode string
+
+const (
+	// AssociationReplace links exactly the given records, unlinking the others
+	AssociationReplace AssociationMode = "replace"
+	// AssociationAppend links the given records, keeping the current ones
+	AssociationAppend AssociationMode = "append"
+	// AssociationRemove unlinks the given records
+	AssociationRemove AssociationMode = "remove"
+)
+
 type gormBookRepository struct {
 	db *gorm.DB
 }
@@ -23,50 +53,213 @@ func NewBookRepository(db *gorm.DB) BookRepository {
 	return &gormBookRepository{db}
 }
 
-func (r *gormBookRepository) Create(book *domain.Book) error {
-	return r.db.Create(book).Error
+func (r *gormBookRepository) Create(ctx context.Context, book *domain.Book) error {
+	// The authors, categories, publisher and series must already exist,
+	// only the links are created. The credits are set by UpdateAuthorCredits
+	return translateError(r.db.WithContext(ctx).
+		Omit("Authors.*", "Categories.*", "Publisher", "Series", "AuthorCredits").
+		Create(book).Error)
 }
 
-func (r *gormBookRepository) FindByID(id string) (*domain.Book, error) {
-	var book domain.Book
-	if err := r.db.
+// withBookAssociations loads the relations returned with every book
+func withBookAssociations(db *gorm.DB) *gorm.DB {
+	return db.
 		Preload("Categories").
 		Preload("Authors").
-		First(&book, id).Error; err != nil {
-		return nil, err
+		Preload("AuthorCredits").
+		Preload("Publisher").
+		Preload("Series")
+}
+
+func (r *gormBookRepository) FindByID(ctx context.Context, id string) (*domain.Book, error) {
+	var book domain.Book
+	if err := r.db.WithContext(ctx).
+		Scopes(withBookAssociations).
+		First(&book, "id = ?", id).Error; err != nil {
+		return nil, translateError(err)
 	}
 
 	return &book, nil
 }
 
-func (r *gormBookRepository) FindByTitle(title string) (*domain.Book, error) {
+func (r *gormBookRepository) FindByTitle(ctx context.Context, title string) (*domain.Book, error) {
 	var book domain.Book
-	if err := r.db.
-		Preload("Categories").
-		Preload("Authors").
+	if err := r.db.WithContext(ctx).
+		Scopes(withBookAssociations).
 		First(&book, "title = ?", title).Error; err != nil {
-		return nil, err
+		return nil, translateError(err)
+	}
+
+	return &book, nil
+}
+
+func (r *gormBookRepository) FindByISBN(ctx context.Context, isbn string) (*domain.Book, error) {
+	var book domain.Book
+	if err := r.db.WithContext(ctx).
+		Scopes(withBookAssociations).
+		First(&book, "isbn = ?", isbn).Error; err != nil {
+		return nil, translateError(err)
 	}
 
 	return &book, nil
 }
 
-func (r *gormBookRepository) FindAll() ([]*domain.Book, error) {
+// FindBySeries lists the volumes of the series ordered by their position
+func (r *gormBookRepository) FindBySeries(ctx context.Context, seriesID string) ([]*domain.Book, error) {
 	var books []*domain.Book
-	if err := r.db.
-		Preload("Categories").
-		Preload("Authors").
+	if err := r.db.WithContext(ctx).
+		Scopes(withBookAssociations).
+		Where("series_id = ?", seriesID).
+		Order("series_position").
 		Find(&books).Error; err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 
 	return books, nil
 }
 
-func (r *gormBookRepository) Update(book *domain.Book) error {
-	return r.db.Save(book).Error
+func (r *gormBookRepository) FindBySeriesPosition(ctx context.Context, seriesID string, position int) (*domain.Book, error) {
+	var book domain.Book
+	if err := r.db.WithContext(ctx).
+		First(&book, "series_id = ? AND series_position = ?", seriesID, position).Error; err != nil {
+		return nil, translateError(err)
+	}
+
+	return &book, nil
+}
+
+// FindVolumeNeighbours finds the volumes right before and after the book
+// in its series, they are nil when there is no such volume. The positions
+// may have gaps, so the closest ones are taken
+func (r *gormBookRepository) FindVolumeNeighbours(ctx context.Context, book *domain.Book) (previous, next *domain.Book, err error) {
+	if book.SeriesID == nil || book.SeriesPosition == nil {
+		return nil, nil, nil
+	}
+
+	find := func(condition string, order string) (*domain.Book, error) {
+		var neighbours []*domain.Book
+		if err := r.db.WithContext(ctx).
+			Where("series_id = ? AND series_position "+condition+" ?", *book.SeriesID, *book.SeriesPosition).
+			Order(order).
+			Limit(1).
+			Find(&neighbours).Error; err != nil {
+			return nil, translateError(err)
+		}
+
+		if len(neighbours) == 0 {
+			return nil, nil
+		}
+
+		return neighbours[0], nil
+	}
+
+	if previous, err = find("<", "series_position DESC"); err != nil {
+		return nil, nil, err
+	}
+	if next, err = find(">", "series_position"); err != nil {
+		return nil, nil, err
+	}
+
+	return previous, next, nil
+}
+
+func (r *gormBookRepository) FindAll(ctx context.Context, filter BookFilter, page PageRequest) ([]*domain.Book, *PageInfo, error) {
+	query := filter.apply(r.db.WithContext(ctx).
+		Model(&domain.Book{}).
+		Scopes(withBookAssociations))
+
+	return paginate(query, page, func(book *domain.Book) string {
+		return book.ID
+	})
+}
+
+// FindInBatches walks every book matching the filter in ID order, loading
+// batchSize books (with their associations) at a time, so the memory
+// used does not grow with the catalogue. An error returned by fn stops it
+func (r *gormBookRepository) FindInBatches(ctx context.Context, filter BookFilter, batchSize int, fn func(books []*domain.Book) error) error {
+	query := filter.apply(r.db.WithContext(ctx).
+		Model(&domain.Book{}).
+		Scopes(withBookAssociations))
+
+	var batch []*domain.Book
+	result := query.FindInBatches(&batch, batchSize, func(_ *gorm.DB, _ int) error {
+		return fn(batch)
+	})
+
+	return translateError(result.Error)
+}
+
+func (r *gormBookRepository) Update(ctx context.Context, book *domain.Book) error {
+	// The associations are changed through UpdateAuthors and UpdateCategories
+	return updateVersioned(r.db.WithContext(ctx), book, &book.Base)
+}
+
+func (r *gormBookRepository) UpdateAuthors(ctx context.Context, book *domain.Book, authors []domain.Author, mode AssociationMode) error {
+	return r.updateAssociation(ctx, book, "Authors", authors, len(authors) == 0, mode)
+}
+
+// UpdateAuthorCredits sets the name each author is credited under on the book,
+// the authors must already be linked to it
+func (r *gormBookRepository) UpdateAuthorCredits(ctx context.Context, bookID string, credits []domain.BookAuthor) error {
+	for _, credit := range credits {
+		if err := r.db.WithContext(ctx).
+			Model(&domain.BookAuthor{}).
+			Where("book_id = ? AND author_id = ?", bookID, credit.AuthorID).
+			Update("credited_as", credit.CreditedAs).Error; err != nil {
+			return translateError(err)
+		}
+	}
+
+	return nil
+}
+
+func (r *gormBookRepository) UpdateCategories(ctx context.Context, book *domain.Book, categories []domain.Category, mode AssociationMode) error {
+	return r.updateAssociation(ctx, book, "Categories", categories, len(categories) == 0, mode)
+}
+
+func (r *gormBookRepository) updateAssociation(ctx context.Context, book *domain.Book, name string, values interface{}, empty bool, mode AssociationMode) error {
+	association := r.db.WithContext(ctx).
+		Model(book).
+		Association(name)
+
+	switch mode {
+	case AssociationReplace:
+		if empty {
+			// Replacing by nothing unlinks every record
+			return translateError(association.Clear())
+		}
+		return translateError(association.Replace(values))
+	case AssociationAppend:
+		return translateError(association.Append(values))
+	case AssociationRemove:
+		return translateError(association.Delete(values))
+	default:
+		return domain.NewValidationError("associations", fmt.Sprintf("unknown association mode %q", mode))
+	}
+}
+
+func (r *gormBookRepository) Delete(ctx context.Context, id string, version int64) error {
+	return deleteVersioned(r.db.WithContext(ctx), &domain.Book{}, id, version)
+}
+
+func (r *gormBookRepository) FindDeleted(ctx context.Context, page PageRequest) ([]*domain.Book, *PageInfo, error) {
+	query := r.db.WithContext(ctx).
+		Model(&domain.Book{}).
+		Scopes(withBookAssociations)
+
+	return findDeleted(query, page, func(book *domain.Book) string {
+		return book.ID
+	})
+}
+
+func (r *gormBookRepository) Restore(ctx context.Context, id string) error {
+	return restoreDeleted(r.db.WithContext(ctx), &domain.Book{}, id)
+}
+
+func (r *gormBookRepository) Purge(ctx context.Context, id string) ([]ReleasedReference, error) {
+	return purgeDeleted(r.db.WithContext(ctx), &domain.Book{}, id, bookReferences)
 }
 
-func (r *gormBookRepository) Delete(id string) error {
-	return r.db.Delete(&domain.Book{}, id).Error
+func (r *gormBookRepository) PurgeDeletedBefore(ctx context.Context, cutoff time.Time, batchSize int, onPurged PurgedFunc) (int64, error) {
+	return purgeExpired(r.db.WithContext(ctx), &domain.Book{}, cutoff, batchSize, bookReferences, onPurged)
 }
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/domain"
+)
+
+// searchConfig is the text search configuration created by the
+// migrations, it applies the portuguese stemming on unaccented words
+const searchConfig = "portuguese_unaccent"
+
+type BookSearchResult struct {
+	Book              *domain.Book
+	Rank              float64
+	TitleHighlight    string
+	SynopsisHighlight string
+}
+
+type bookSearchRow struct {
+	ID                string
+	Rank              float64
+	TitleHighlight    string
+	SynopsisHighlight string
+}
+
+// The highlights are HTML, so the text of the book is escaped before the
+// matches are marked, otherwise its own markup would reach the clients
+const bookSearchQuery = `
+SELECT
+    books.id,
+    ts_rank(books.search_vector, query) AS rank,
+    ts_headline(@config, replace(replace(replace(books.title, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), query, 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true') AS title_highlight,
+    ts_headline(@config, replace(replace(replace(books.synopsis, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), query, 'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10') AS synopsis_highlight
+FROM books, websearch_to_tsquery(@config, @term) AS query
+WHERE books.deleted_at IS NULL
+    AND books.search_vector @@ query
+ORDER BY rank DESC, books.id
+LIMIT @limit OFFSET @offset`
+
+func (r *gormBookRepository) Search(ctx context.Context, term string, limit, offset int) ([]*BookSearchResult, error) {
+	var rows []bookSearchRow
+	if err := r.db.WithContext(ctx).
+		Raw(bookSearchQuery, map[string]interface{}{
+			"config": searchConfig,
+			"term":   term,
+			"limit":  limit,
+			"offset": offset,
+		}).
+		Scan(&rows).Error; err != nil {
+		return nil, translateError(err)
+	}
+
+	if len(rows) == 0 {
+		return []*BookSearchResult{}, nil
+	}
+
+	ids := make([]string, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, row.ID)
+	}
+
+	var books []*domain.Book
+	if err := r.db.WithContext(ctx).
+		Scopes(withBookAssociations).
+		Find(&books, "id IN ?", ids).Error; err != nil {
+		return nil, translateError(err)
+	}
+
+	booksByID := make(map[string]*domain.Book, len(books))
+	for _, book := range books {
+		booksByID[book.ID] = book
+	}
+
+	// Keep the order given by the rank
+	results := make([]*BookSearchResult, 0, len(rows))
+	for _, row := range rows {
+		book, ok := booksByID[row.ID]
+		if !ok {
+			continue
+		}
+
+		results = append(results, &BookSearchResult{
+			Book:              book,
+			Rank:              row.Rank,
+			TitleHighlight:    row.TitleHighlight,
+			SynopsisHighlight: row.SynopsisHighlight,
+		})
+	}
+
+	return results, nil
+}
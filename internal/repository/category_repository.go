@@ -1,18 +1,30 @@
 package repository
 
 import (
+	"context"
+	"errors"
+	"time"
+
 	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/domain"
 
 	"gorm.io/gorm"
 )
 
 type CategoryRepository interface {
-	Create(category *domain.Category) error
-	FindByID(id string) (*domain.Category, error)
-	FindByName(name string) (*domain.Category, error)
-	FindAll() ([]*domain.Category, error)
-	Update(category *domain.Category) error
-	Delete(id string) error
+	Create(ctx context.Context, category *domain.Category) error
+	FindByID(ctx context.Context, id string) (*domain.Category, error)
+	FindByName(ctx context.Context, name string) (*domain.Category, error)
+	FindAll(ctx context.Context, page PageRequest) ([]*domain.Category, *PageInfo, error)
+	FindAncestors(ctx context.Context, id string) ([]*domain.Category, error)
+	FindSubtree(ctx context.Context, id string) ([]*domain.Category, error)
+	FindTree(ctx context.Context) ([]*domain.Category, error)
+	Update(ctx context.Context, category *domain.Category) error
+	Delete(ctx context.Context, id string, version int64) error
+	FindDeleted(ctx context.Context, page PageRequest) ([]*domain.Category, *PageInfo, error)
+	Restore(ctx context.Context, id string) error
+	Purge(ctx context.Context, id string) ([]ReleasedReference, error)
+	PurgeDeletedBefore(ctx context.Context, cutoff time.Time, batchSize int, onPurged PurgedFunc) (int64, error)
+	Merge(ctx context.Context, sourceID, targetID string, version int64) error
 }
 
 type gormCategoriesRepository struct {
@@ -23,44 +35,77 @@ func NewCategoryRepository(db *gorm.DB) CategoryRepository {
 	return &gormCategoriesRepository{db}
 }
 
-func (r *gormCategoriesRepository) Create(category *domain.Category) error {
-	return r.db.Create(category).Error
+func (r *gormCategoriesRepository) Create(ctx context.Context, category *domain.Category) error {
+	return translateError(r.db.WithContext(ctx).Create(category).Error)
 }
 
-func (r *gormCategoriesRepository) FindByID(id string) (*domain.Category, error) {
+// FindByID finds the category by its ID or, when the category
+// was merged into another one, finds the category it was merged into
+func (r *gormCategoriesRepository) FindByID(ctx context.Context, id string) (*domain.Category, error) {
 	var category domain.Category
-	if err := r.db.
-		First(&category, id).Error; err != nil {
-		return nil, err
+	err := r.db.WithContext(ctx).
+		First(&category, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = r.db.WithContext(ctx).
+			Joins("JOIN category_redirects ON category_redirects.category_id = categories.id").
+			First(&category, "category_redirects.old_id = ?", id).Error
+	}
+	if err != nil {
+		return nil, translateError(err)
 	}
 
 	return &category, nil
 }
 
-func (r *gormCategoriesRepository) FindByName(name string) (*domain.Category, error) {
+// FindByName finds the category by its name, ignoring the case, the accents and the spaces
+func (r *gormCategoriesRepository) FindByName(ctx context.Context, name string) (*domain.Category, error) {
 	var category domain.Category
-	if err := r.db.
-		First(&category, "name = ?", name).Error; err != nil {
-		return nil, err
+	if err := r.db.WithContext(ctx).
+		First(&category, "name_key = normalize_name(?)", name).Error; err != nil {
+		return nil, translateError(err)
 	}
 
 	return &category, nil
 }
 
-func (r *gormCategoriesRepository) FindAll() ([]*domain.Category, error) {
-	var categories []*domain.Category
-	if err := r.db.
-		Find(&categories).Error; err != nil {
-		return nil, err
+func (r *gormCategoriesRepository) FindAll(ctx context.Context, page PageRequest) ([]*domain.Category, *PageInfo, error) {
+	return paginate(r.db.WithContext(ctx).Model(&domain.Category{}), page, func(category *domain.Category) string {
+		return category.ID
+	})
+}
+
+func (r *gormCategoriesRepository) Update(ctx context.Context, category *domain.Category) error {
+	return updateVersioned(r.db.WithContext(ctx), category, &category.Base)
+}
+
+func (r *gormCategoriesRepository) Delete(ctx context.Context, id string, version int64) error {
+	return deleteVersioned(r.db.WithContext(ctx), &domain.Category{}, id, version)
+}
+
+func (r *gormCategoriesRepository) FindDeleted(ctx context.Context, page PageRequest) ([]*domain.Category, *PageInfo, error) {
+	return findDeleted(r.db.WithContext(ctx).Model(&domain.Category{}), page, func(category *domain.Category) string {
+		return category.ID
+	})
+}
+
+func (r *gormCategoriesRepository) Restore(ctx context.Context, id string) error {
+	if err := checkNotMerged(r.db.WithContext(ctx), categoryRedirects, id); err != nil {
+		return err
 	}
 
-	return categories, nil
+	return restoreDeleted(r.db.WithContext(ctx), &domain.Category{}, id)
+}
+
+func (r *gormCategoriesRepository) Purge(ctx context.Context, id string) ([]ReleasedReference, error) {
+	return purgeDeleted(r.db.WithContext(ctx), &domain.Category{}, id, categoryReferences)
 }
 
-func (r *gormCategoriesRepository) Update(category *domain.Category) error {
-	return r.db.Save(category).Error
+func (r *gormCategoriesRepository) PurgeDeletedBefore(ctx context.Context, cutoff time.Time, batchSize int, onPurged PurgedFunc) (int64, error) {
+	return purgeExpired(r.db.WithContext(ctx), &domain.Category{}, cutoff, batchSize, categoryReferences, onPurged)
 }
 
-func (r *gormCategoriesRepository) Delete(id string) error {
-	return r.db.Delete(&domain.Category{}, id).Error
+// Merge moves the books and subcategories of the source category to the
+// target category and soft deletes the source, its ID keeps resolving to the target
+func (r *gormCategoriesRepository) Merge(ctx context.Context, sourceID, targetID string, version int64) error {
+	return mergeInto(r.db.WithContext(ctx), &domain.Category{}, sourceID, targetID, version, categoryMerges, categoryRedirects)
 }
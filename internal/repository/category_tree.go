@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// maxCategoryDepth stops the recursive queries, the service does not let
+// a cycle be created but a bad row must not make them run forever
+const maxCategoryDepth = 64
+
+// FindAncestors returns the category followed by its parent, the parent
+// of its parent and so on up to the root category
+func (r *gormCategoriesRepository) FindAncestors(ctx context.Context, id string) ([]*domain.Category, error) {
+	var categories []*domain.Category
+	if err := r.db.WithContext(ctx).Raw(`
+		WITH RECURSIVE ancestors AS (
+			SELECT id, parent_id, 0 AS depth
+			FROM categories
+			WHERE id = ? AND deleted_at IS NULL
+			UNION ALL
+			SELECT parent.id, parent.parent_id, ancestors.depth + 1
+			FROM categories parent
+			JOIN ancestors ON parent.id = ancestors.parent_id
+			WHERE parent.deleted_at IS NULL AND ancestors.depth < ?
+		)
+		SELECT categories.*
+		FROM categories
+		JOIN ancestors ON ancestors.id = categories.id
+		ORDER BY ancestors.depth`,
+		id, maxCategoryDepth,
+	).Scan(&categories).Error; err != nil {
+		return nil, translateError(err)
+	}
+
+	if len(categories) == 0 {
+		return nil, translateError(gorm.ErrRecordNotFound)
+	}
+
+	return categories, nil
+}
+
+// FindSubtree returns the category followed by all of its descendants,
+// the categories closer to it come first
+func (r *gormCategoriesRepository) FindSubtree(ctx context.Context, id string) ([]*domain.Category, error) {
+	var categories []*domain.Category
+	if err := r.db.WithContext(ctx).Raw(`
+		WITH RECURSIVE subtree AS (
+			SELECT id, 0 AS depth
+			FROM categories
+			WHERE id = ? AND deleted_at IS NULL
+			UNION ALL
+			SELECT child.id, subtree.depth + 1
+			FROM categories child
+			JOIN subtree ON child.parent_id = subtree.id
+			WHERE child.deleted_at IS NULL AND subtree.depth < ?
+		)
+		SELECT categories.*
+		FROM categories
+		JOIN subtree ON subtree.id = categories.id
+		ORDER BY subtree.depth, categories.name`,
+		id, maxCategoryDepth,
+	).Scan(&categories).Error; err != nil {
+		return nil, translateError(err)
+	}
+
+	if len(categories) == 0 {
+		return nil, translateError(gorm.ErrRecordNotFound)
+	}
+
+	return categories, nil
+}
+
+// FindTree returns every category, the tree is built by the caller
+func (r *gormCategoriesRepository) FindTree(ctx context.Context) ([]*domain.Category, error) {
+	var categories []*domain.Category
+	if err := r.db.WithContext(ctx).
+		Order("name").
+		Find(&categories).Error; err != nil {
+		return nil, translateError(err)
+	}
+
+	return categories, nil
+}
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/domain"
+	"gorm.io/gorm"
+)
+
+// translateError wraps the database errors with the matching domain error,
+// the original error is kept in the chain
+func translateError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, domain.ErrValidation),
+		errors.Is(err, domain.ErrNotFound),
+		errors.Is(err, domain.ErrConflict),
+		errors.Is(err, domain.ErrPreconditionFailed),
+		errors.Is(err, domain.ErrInternal):
+		return err
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return fmt.Errorf("%w: %w", domain.ErrNotFound, err)
+	case errors.Is(err, gorm.ErrDuplicatedKey),
+		errors.Is(err, gorm.ErrForeignKeyViolated):
+		return fmt.Errorf("%w: %w", domain.ErrConflict, err)
+	default:
+		return fmt.Errorf("%w: %w", domain.ErrInternal, err)
+	}
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// mergedColumn is a column referencing the merged records, its rows are moved
+// to the surviving record. When uniqueWith is set the column is part of a
+// unique key with it, so the rows the survivor already has are dropped
+type mergedColumn struct {
+	table      string
+	column     string
+	uniqueWith string
+}
+
+// redirectTable keeps, for each merged record, the record it was merged into
+type redirectTable struct {
+	table  string
+	column string
+}
+
+var (
+	authorMerges = []mergedColumn{
+		{table: "book_authors", column: "author_id", uniqueWith: "book_id"},
+		{table: "author_aliases", column: "author_id"},
+	}
+	authorRedirects = redirectTable{table: "author_redirects", column: "author_id"}
+
+	categoryMerges = []mergedColumn{
+		{table: "book_categories", column: "category_id", uniqueWith: "book_id"},
+		{table: "categories", column: "parent_id"},
+	}
+	categoryRedirects = redirectTable{table: "category_redirects", column: "category_id"}
+)
+
+// mergeInto moves the references of the source record to the target, soft
+// deletes the source and redirects its ID (and the IDs previously merged into it)
+// to the target. When version is not zero the source must still have that version
+func mergeInto(db *gorm.DB, model interface{}, sourceID, targetID string, version int64, columns []mergedColumn, redirects redirectTable) error {
+	return translateError(db.Transaction(func(tx *gorm.DB) error {
+		if err := deleteVersioned(tx, model, sourceID, version); err != nil {
+			return err
+		}
+
+		for _, column := range columns {
+			if column.uniqueWith != "" {
+				if err := tx.Exec(fmt.Sprintf(
+					"DELETE FROM %[1]s moved USING %[1]s kept "+
+						"WHERE moved.%[2]s = ? AND kept.%[2]s = ? AND kept.%[3]s = moved.%[3]s",
+					column.table, column.column, column.uniqueWith,
+				), sourceID, targetID).Error; err != nil {
+					return err
+				}
+			}
+
+			if err := tx.Exec(fmt.Sprintf(
+				"UPDATE %s SET %s = ? WHERE %s = ?",
+				column.table, column.column, column.column,
+			), targetID, sourceID).Error; err != nil {
+				return err
+			}
+		}
+
+		// The target is a live record, so it no longer needs a redirect
+		// of its own, and the records merged into the source now resolve to it
+		if err := tx.Exec(fmt.Sprintf(
+			"DELETE FROM %s WHERE old_id = ?",
+			redirects.table,
+		), targetID).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Exec(fmt.Sprintf(
+			"UPDATE %s SET %s = ? WHERE %s = ?",
+			redirects.table, redirects.column, redirects.column,
+		), targetID, sourceID).Error; err != nil {
+			return err
+		}
+
+		return tx.Exec(fmt.Sprintf(
+			"INSERT INTO %[1]s (old_id, %[2]s) VALUES (?, ?) "+
+				"ON CONFLICT (old_id) DO UPDATE SET %[2]s = EXCLUDED.%[2]s",
+			redirects.table, redirects.column,
+		), sourceID, targetID).Error
+	}))
+}
+
+// checkNotMerged fails with a conflict when the record was merged into another one.
+// Its links were moved to that record, so restoring it would bring back an empty
+// record while its ID keeps resolving to the other one
+func checkNotMerged(db *gorm.DB, redirects redirectTable, id string) error {
+	var count int64
+	if err := db.
+		Table(redirects.table).
+		Where("old_id = ?", id).
+		Count(&count).Error; err != nil {
+		return translateError(err)
+	}
+	if count > 0 {
+		return fmt.Errorf("%w: the record was merged into another one", domain.ErrConflict)
+	}
+
+	return nil
+}
@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SortDirection string
+
+const (
+	SortAsc  SortDirection = "asc"
+	SortDesc SortDirection = "desc"
+)
+
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+const (
+	cursorNext = "next"
+	cursorPrev = "prev"
+)
+
+var ErrInvalidCursor = domain.NewValidationError("cursor", "is invalid")
+
+// PageRequest describes a keyset page over the records IDs.
+// Since the IDs are UUIDv7 they are ordered by creation time,
+// so paginating by ID is the same as paginating by creation date
+type PageRequest struct {
+	Limit  int
+	Cursor string
+	Sort   SortDirection
+}
+
+type PageInfo struct {
+	Limit      int
+	Sort       SortDirection
+	NextCursor string
+	PrevCursor string
+	HasNext    bool
+	HasPrev    bool
+}
+
+// cursor is the content of the opaque cursor sent to the clients
+type cursor struct {
+	ID        string        `json:"id"`
+	Direction string        `json:"dir"`
+	Sort      SortDirection `json:"sort"`
+}
+
+func (p PageRequest) normalize() PageRequest {
+	if p.Limit <= 0 {
+		p.Limit = DefaultPageLimit
+	}
+	if p.Limit > MaxPageLimit {
+		p.Limit = MaxPageLimit
+	}
+	if p.Sort != SortDesc {
+		p.Sort = SortAsc
+	}
+
+	return p
+}
+
+func encodeCursor(c cursor) string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeCursor(value string) (*cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var c cursor
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	if c.ID == "" || (c.Direction != cursorNext && c.Direction != cursorPrev) ||
+		(c.Sort != SortAsc && c.Sort != SortDesc) {
+		return nil, ErrInvalidCursor
+	}
+
+	return &c, nil
+}
+
+// paginate runs the query fetching a single keyset page, one extra row
+// is loaded to know if there are more records after the page
+func paginate[T any](query *gorm.DB, page PageRequest, idOf func(T) string) ([]T, *PageInfo, error) {
+	page = page.normalize()
+
+	var current *cursor
+	if page.Cursor != "" {
+		c, err := decodeCursor(page.Cursor)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// The cursor carries the sort it was generated with
+		current = c
+		page.Sort = c.Sort
+	}
+
+	idColumn := clause.Column{Table: clause.CurrentTable, Name: "id"}
+	backward := current != nil && current.Direction == cursorPrev
+
+	// Walking backwards means reading in the opposite order
+	// and reversing the result afterwards
+	descending := page.Sort == SortDesc
+	if backward {
+		descending = !descending
+	}
+
+	if current != nil {
+		if descending {
+			query = query.Where(clause.Lt{Column: idColumn, Value: current.ID})
+		} else {
+			query = query.Where(clause.Gt{Column: idColumn, Value: current.ID})
+		}
+	}
+
+	var items []T
+	if err := query.
+		Order(clause.OrderByColumn{Column: idColumn, Desc: descending}).
+		Limit(page.Limit + 1).
+		Find(&items).Error; err != nil {
+		return nil, nil, translateError(err)
+	}
+
+	hasMore := len(items) > page.Limit
+	if hasMore {
+		items = items[:page.Limit]
+	}
+
+	if backward {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+	}
+
+	info := &PageInfo{
+		Limit: page.Limit,
+		Sort:  page.Sort,
+	}
+
+	if backward {
+		info.HasPrev = hasMore
+		info.HasNext = true
+	} else {
+		info.HasNext = hasMore
+		info.HasPrev = current != nil
+	}
+
+	if len(items) > 0 {
+		if info.HasNext {
+			info.NextCursor = encodeCursor(cursor{
+				ID:        idOf(items[len(items)-1]),
+				Direction: cursorNext,
+				Sort:      page.Sort,
+			})
+		}
+		if info.HasPrev {
+			info.PrevCursor = encodeCursor(cursor{
+				ID:        idOf(items[0]),
+				Direction: cursorPrev,
+				Sort:      page.Sort,
+			})
+		}
+	}
+
+	return items, info, nil
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	tests := []cursor{
+		{ID: "0191a2b3-c4d5-7e6f-8a9b-0c1d2e3f4a5b", Direction: cursorNext, Sort: SortAsc},
+		{ID: "0191a2b3-c4d5-7e6f-8a9b-0c1d2e3f4a5b", Direction: cursorPrev, Sort: SortDesc},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Direction+"-"+string(tt.Sort), func(t *testing.T) {
+			encoded := encodeCursor(tt)
+
+			got, err := decodeCursor(encoded)
+			if err != nil {
+				t.Fatalf("decodeCursor(%q) error = %v", encoded, err)
+			}
+			if !reflect.DeepEqual(*got, tt) {
+				t.Errorf("decodeCursor(encodeCursor(%+v)) = %+v", tt, *got)
+			}
+		})
+	}
+}
+
+func TestDecodeCursorInvalid(t *testing.T) {
+	encode := func(raw string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(raw))
+	}
+
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{name: "not base64", value: "not a cursor!"},
+		{name: "not JSON", value: encode("id=a")},
+		{name: "missing ID", value: encode(`{"dir":"next","sort":"asc"}`)},
+		{name: "unknown direction", value: encode(`{"id":"a","dir":"up","sort":"asc"}`)},
+		{name: "unknown sort", value: encode(`{"id":"a","dir":"next","sort":"random"}`)},
+		{name: "missing sort", value: encode(`{"id":"a","dir":"prev"}`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := decodeCursor(tt.value); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("decodeCursor(%q) error = %v, want %v", tt.value, err, ErrInvalidCursor)
+			}
+		})
+	}
+}
+
+func TestPageRequestNormalize(t *testing.T) {
+	tests := []struct {
+		name string
+		page PageRequest
+		want PageRequest
+	}{
+		{name: "defaults", page: PageRequest{}, want: PageRequest{Limit: DefaultPageLimit, Sort: SortAsc}},
+		{name: "negative limit", page: PageRequest{Limit: -1}, want: PageRequest{Limit: DefaultPageLimit, Sort: SortAsc}},
+		{name: "limit above the maximum", page: PageRequest{Limit: MaxPageLimit + 1, Sort: SortDesc}, want: PageRequest{Limit: MaxPageLimit, Sort: SortDesc}},
+		{name: "unknown sort", page: PageRequest{Limit: 5, Sort: "random"}, want: PageRequest{Limit: 5, Sort: SortAsc}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.page.normalize(); got != tt.want {
+				t.Errorf("normalize() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
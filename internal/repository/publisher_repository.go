@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+type PublisherRepository interface {
+	Create(ctx context.Context, publisher *domain.Publisher) error
+	FindByID(ctx context.Context, id string) (*domain.Publisher, error)
+	FindByName(ctx context.Context, name string) (*domain.Publisher, error)
+	FindAll(ctx context.Context, page PageRequest) ([]*domain.Publisher, *PageInfo, error)
+	Update(ctx context.Context, publisher *domain.Publisher) error
+	Delete(ctx context.Context, id string, version int64) error
+	FindDeleted(ctx context.Context, page PageRequest) ([]*domain.Publisher, *PageInfo, error)
+	Restore(ctx context.Context, id string) error
+	Purge(ctx context.Context, id string) ([]ReleasedReference, error)
+	PurgeDeletedBefore(ctx context.Context, cutoff time.Time, batchSize int, onPurged PurgedFunc) (int64, error)
+}
+
+type gormPublisherRepository struct {
+	db *gorm.DB
+}
+
+func NewPublisherRepository(db *gorm.DB) PublisherRepository {
+	return &gormPublisherRepository{db}
+}
+
+func (r *gormPublisherRepository) Create(ctx context.Context, publisher *domain.Publisher) error {
+	return translateError(r.db.WithContext(ctx).Create(publisher).Error)
+}
+
+func (r *gormPublisherRepository) FindByID(ctx context.Context, id string) (*domain.Publisher, error) {
+	var publisher domain.Publisher
+	if err := r.db.WithContext(ctx).
+		First(&publisher, "id = ?", id).Error; err != nil {
+		return nil, translateError(err)
+	}
+
+	return &publisher, nil
+}
+
+// FindByName finds the publisher by its name, ignoring the case, the accents and the spaces
+func (r *gormPublisherRepository) FindByName(ctx context.Context, name string) (*domain.Publisher, error) {
+	var publisher domain.Publisher
+	if err := r.db.WithContext(ctx).
+		First(&publisher, "name_key = normalize_name(?)", name).Error; err != nil {
+		return nil, translateError(err)
+	}
+
+	return &publisher, nil
+}
+
+func (r *gormPublisherRepository) FindAll(ctx context.Context, page PageRequest) ([]*domain.Publisher, *PageInfo, error) {
+	return paginate(r.db.WithContext(ctx).Model(&domain.Publisher{}), page, func(publisher *domain.Publisher) string {
+		return publisher.ID
+	})
+}
+
+func (r *gormPublisherRepository) Update(ctx context.Context, publisher *domain.Publisher) error {
+	return updateVersioned(r.db.WithContext(ctx), publisher, &publisher.Base)
+}
+
+func (r *gormPublisherRepository) Delete(ctx context.Context, id string, version int64) error {
+	return deleteVersioned(r.db.WithContext(ctx), &domain.Publisher{}, id, version)
+}
+
+func (r *gormPublisherRepository) FindDeleted(ctx context.Context, page PageRequest) ([]*domain.Publisher, *PageInfo, error) {
+	return findDeleted(r.db.WithContext(ctx).Model(&domain.Publisher{}), page, func(publisher *domain.Publisher) string {
+		return publisher.ID
+	})
+}
+
+func (r *gormPublisherRepository) Restore(ctx context.Context, id string) error {
+	return restoreDeleted(r.db.WithContext(ctx), &domain.Publisher{}, id)
+}
+
+func (r *gormPublisherRepository) Purge(ctx context.Context, id string) ([]ReleasedReference, error) {
+	return purgeDeleted(r.db.WithContext(ctx), &domain.Publisher{}, id, publisherReferences)
+}
+
+func (r *gormPublisherRepository) PurgeDeletedBefore(ctx context.Context, cutoff time.Time, batchSize int, onPurged PurgedFunc) (int64, error) {
+	return purgeExpired(r.db.WithContext(ctx), &domain.Publisher{}, cutoff, batchSize, publisherReferences, onPurged)
+}
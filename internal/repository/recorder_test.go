@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// statementRecorder is a database/sql connector recording the statements
+// run by the repositories, so their SQL can be checked without a database.
+// Every statement affects one row and every query returns no rows
+type statementRecorder struct {
+	mu         sync.Mutex
+	statements []string
+}
+
+// newRecordedDB opens a gorm session over a new statement recorder
+func newRecordedDB(t *testing.T) (*gorm.DB, *statementRecorder) {
+	t.Helper()
+
+	recorder := &statementRecorder{}
+	db, err := gorm.Open(
+		postgres.New(postgres.Config{Conn: sql.OpenDB(recorder)}),
+		&gorm.Config{Logger: logger.Discard},
+	)
+	if err != nil {
+		t.Fatalf("error while opening the recorded database: %v", err)
+	}
+
+	return db, recorder
+}
+
+// indexOf returns the position of the first statement starting with
+// prefix, or -1 when no statement does
+func (r *statementRecorder) indexOf(prefix string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, statement := range r.statements {
+		if strings.HasPrefix(statement, prefix) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (r *statementRecorder) record(statement string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.statements = append(r.statements, statement)
+}
+
+func (r *statementRecorder) Connect(context.Context) (driver.Conn, error) {
+	return &recordedConn{r}, nil
+}
+
+func (r *statementRecorder) Driver() driver.Driver {
+	return nil
+}
+
+type recordedConn struct {
+	recorder *statementRecorder
+}
+
+func (c *recordedConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, driver.ErrSkip
+}
+
+func (c *recordedConn) Close() error {
+	return nil
+}
+
+func (c *recordedConn) Begin() (driver.Tx, error) {
+	return recordedTx{}, nil
+}
+
+func (c *recordedConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	c.recorder.record(query)
+	return driver.RowsAffected(1), nil
+}
+
+func (c *recordedConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	c.recorder.record(query)
+	return emptyRows{}, nil
+}
+
+type recordedTx struct{}
+
+func (recordedTx) Commit() error {
+	return nil
+}
+
+func (recordedTx) Rollback() error {
+	return nil
+}
+
+type emptyRows struct{}
+
+func (emptyRows) Columns() []string {
+	return nil
+}
+
+func (emptyRows) Close() error {
+	return nil
+}
+
+func (emptyRows) Next([]driver.Value) error {
+	return io.EOF
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+type SeriesRepository interface {
+	Create(ctx context.Context, series *domain.Series) error
+	FindByID(ctx context.Context, id string) (*domain.Series, error)
+	FindByName(ctx context.Context, name string) (*domain.Series, error)
+	FindAll(ctx context.Context, page PageRequest) ([]*domain.Series, *PageInfo, error)
+	Update(ctx context.Context, series *domain.Series) error
+	Delete(ctx context.Context, id string, version int64) error
+	FindDeleted(ctx context.Context, page PageRequest) ([]*domain.Series, *PageInfo, error)
+	Restore(ctx context.Context, id string) error
+	Purge(ctx context.Context, id string) ([]ReleasedReference, error)
+	PurgeDeletedBefore(ctx context.Context, cutoff time.Time, batchSize int, onPurged PurgedFunc) (int64, error)
+}
+
+type gormSeriesRepository struct {
+	db *gorm.DB
+}
+
+func NewSeriesRepository(db *gorm.DB) SeriesRepository {
+	return &gormSeriesRepository{db}
+}
+
+func (r *gormSeriesRepository) Create(ctx context.Context, series *domain.Series) error {
+	return translateError(r.db.WithContext(ctx).Create(series).Error)
+}
+
+func (r *gormSeriesRepository) FindByID(ctx context.Context, id string) (*domain.Series, error) {
+	var series domain.Series
+	if err := r.db.WithContext(ctx).
+		First(&series, "id = ?", id).Error; err != nil {
+		return nil, translateError(err)
+	}
+
+	return &series, nil
+}
+
+// FindByName finds the series by its name, ignoring the case, the accents and the spaces
+func (r *gormSeriesRepository) FindByName(ctx context.Context, name string) (*domain.Series, error) {
+	var series domain.Series
+	if err := r.db.WithContext(ctx).
+		First(&series, "name_key = normalize_name(?)", name).Error; err != nil {
+		return nil, translateError(err)
+	}
+
+	return &series, nil
+}
+
+func (r *gormSeriesRepository) FindAll(ctx context.Context, page PageRequest) ([]*domain.Series, *PageInfo, error) {
+	return paginate(r.db.WithContext(ctx).Model(&domain.Series{}), page, func(series *domain.Series) string {
+		return series.ID
+	})
+}
+
+func (r *gormSeriesRepository) Update(ctx context.Context, series *domain.Series) error {
+	return updateVersioned(r.db.WithContext(ctx), series, &series.Base)
+}
+
+func (r *gormSeriesRepository) Delete(ctx context.Context, id string, version int64) error {
+	return deleteVersioned(r.db.WithContext(ctx), &domain.Series{}, id, version)
+}
+
+func (r *gormSeriesRepository) FindDeleted(ctx context.Context, page PageRequest) ([]*domain.Series, *PageInfo, error) {
+	return findDeleted(r.db.WithContext(ctx).Model(&domain.Series{}), page, func(series *domain.Series) string {
+		return series.ID
+	})
+}
+
+func (r *gormSeriesRepository) Restore(ctx context.Context, id string) error {
+	return restoreDeleted(r.db.WithContext(ctx), &domain.Series{}, id)
+}
+
+func (r *gormSeriesRepository) Purge(ctx context.Context, id string) ([]ReleasedReference, error) {
+	return purgeDeleted(r.db.WithContext(ctx), &domain.Series{}, id, seriesReferences)
+}
+
+func (r *gormSeriesRepository) PurgeDeletedBefore(ctx context.Context, cutoff time.Time, batchSize int, onPurged PurgedFunc) (int64, error) {
+	return purgeExpired(r.db.WithContext(ctx), &domain.Series{}, cutoff, batchSize, seriesReferences, onPurged)
+}
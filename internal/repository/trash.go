@@ -0,0 +1,227 @@
+package repository
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// referencingColumn is a column referencing the purged records. The rows
+// of the join tables are deleted, while the rows that must be kept have
+// the column (and the clearColumns depending on it) set to NULL
+type referencingColumn struct {
+	table        string
+	column       string
+	keepRows     bool
+	clearColumns []string
+}
+
+var (
+	bookReferences = []referencingColumn{
+		{table: "book_authors", column: "book_id"},
+		{table: "book_categories", column: "book_id"},
+	}
+	authorReferences = []referencingColumn{
+		{table: "book_authors", column: "author_id"},
+		{table: "author_aliases", column: "author_id"},
+		{table: "author_redirects", column: "author_id"},
+	}
+	categoryReferences = []referencingColumn{
+		{table: "book_categories", column: "category_id"},
+		{table: "categories", column: "parent_id", keepRows: true},
+		{table: "category_redirects", column: "category_id"},
+	}
+	publisherReferences = []referencingColumn{
+		{table: "books", column: "publisher_id", keepRows: true},
+	}
+	seriesReferences = []referencingColumn{
+		{table: "books", column: "series_id", keepRows: true, clearColumns: []string{"series_position"}},
+	}
+)
+
+// ReleasedReference is a row kept by a purge, which had its columns referencing
+// the purged records set to NULL and its version increased. Before holds the
+// values those columns had, keyed by the column name
+type ReleasedReference struct {
+	Table  string
+	ID     string
+	Before map[string]interface{}
+}
+
+// findDeleted lists a page of the soft deleted records of the query model
+func findDeleted[T any](query *gorm.DB, page PageRequest, idOf func(T) string) ([]T, *PageInfo, error) {
+	return paginate(query.Unscoped().Where("deleted_at IS NOT NULL"), page, idOf)
+}
+
+// restoreDeleted clears the deletion date of a soft deleted record.
+// The soft delete keeps the rows on the join tables, so the
+// restored record gets back its links to the other records
+func restoreDeleted(db *gorm.DB, model interface{}, id string) error {
+	result := db.
+		Unscoped().
+		Model(model).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Updates(map[string]interface{}{
+			"deleted_at": nil,
+			"version":    gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return translateError(gorm.ErrRecordNotFound)
+	}
+
+	return nil
+}
+
+// purgeDeleted permanently deletes a soft deleted record and releases its
+// references, returning the rows that were kept. They are released first, as
+// the foreign keys kept on the rows (books.series_id, categories.parent_id) would
+// refuse the delete. When the record is not in the trash the transaction rolls the release back
+func purgeDeleted(db *gorm.DB, model interface{}, id string, references []referencingColumn) ([]ReleasedReference, error) {
+	var released []ReleasedReference
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		released, err = releaseReferences(tx, references, []string{id})
+		if err != nil {
+			return err
+		}
+
+		result := tx.
+			Unscoped().
+			Where("id = ? AND deleted_at IS NOT NULL", id).
+			Delete(model)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	return released, nil
+}
+
+// PurgedFunc is called with the IDs of each batch of purged records and the rows
+// released by the batch, using the repositories of the batch transaction.
+// Returning an error rolls back the batch
+type PurgedFunc func(repos *Repositories, ids []string, released []ReleasedReference) error
+
+// purgeExpired permanently deletes, in batches, the records soft deleted
+// before the cutoff and releases their references. Each batch runs in its own
+// transaction so a long purge does not hold the locks for too long
+func purgeExpired(db *gorm.DB, model interface{}, cutoff time.Time, batchSize int, references []referencingColumn, onPurged PurgedFunc) (int64, error) {
+	var total int64
+
+	for {
+		var ids []string
+		if err := db.
+			Unscoped().
+			Model(model).
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).
+			Order("id").
+			Limit(batchSize).
+			Pluck("id", &ids).Error; err != nil {
+			return total, translateError(err)
+		}
+
+		if len(ids) == 0 {
+			return total, nil
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			released, err := releaseReferences(tx, references, ids)
+			if err != nil {
+				return err
+			}
+
+			result := tx.
+				Unscoped().
+				Where("id IN ?", ids).
+				Delete(model)
+			if result.Error != nil {
+				return result.Error
+			}
+
+			if onPurged != nil {
+				if err := onPurged(NewRepositories(tx), ids, released); err != nil {
+					return err
+				}
+			}
+
+			total += result.RowsAffected
+			return nil
+		})
+		if err != nil {
+			return total, translateError(err)
+		}
+
+		if len(ids) < batchSize {
+			return total, nil
+		}
+	}
+}
+
+// releaseReferences deletes the join table rows referencing the given IDs and sets
+// to NULL the references kept on the other rows, increasing their version so the
+// clients holding them get a conflict. The kept rows are locked and read before
+// the update, and returned with the values they had
+func releaseReferences(tx *gorm.DB, references []referencingColumn, ids []string) ([]ReleasedReference, error) {
+	var released []ReleasedReference
+
+	for _, reference := range references {
+		if !reference.keepRows {
+			if err := tx.
+				Exec("DELETE FROM "+reference.table+" WHERE "+reference.column+" IN ?", ids).
+				Error; err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		columns := append([]string{reference.column}, reference.clearColumns...)
+
+		var rows []map[string]interface{}
+		if err := tx.
+			Table(reference.table).
+			Select(append([]string{"id"}, columns...)).
+			Where(reference.column+" IN ?", ids).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Find(&rows).Error; err != nil {
+			return nil, err
+		}
+
+		assignments := make([]string, 0, len(columns)+1)
+		for _, column := range columns {
+			assignments = append(assignments, column+" = NULL")
+		}
+		assignments = append(assignments, "version = version + 1")
+
+		if err := tx.
+			Exec("UPDATE "+reference.table+" SET "+strings.Join(assignments, ", ")+" WHERE "+reference.column+" IN ?", ids).
+			Error; err != nil {
+			return nil, err
+		}
+
+		for _, row := range rows {
+			id, _ := row["id"].(string)
+			before := make(map[string]interface{}, len(columns))
+			for _, column := range columns {
+				before[column] = row[column]
+			}
+
+			released = append(released, ReleasedReference{Table: reference.table, ID: id, Before: before})
+		}
+	}
+
+	return released, nil
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestPurgeReleasesReferencesBeforeDeleting(t *testing.T) {
+	tests := []struct {
+		name    string
+		purge   func(db *gorm.DB) error
+		release string
+		delete  string
+	}{
+		{
+			name: "series with books",
+			purge: func(db *gorm.DB) error {
+				_, err := NewSeriesRepository(db).Purge(context.Background(), "series-id")
+				return err
+			},
+			release: "UPDATE books SET series_id = NULL, series_position = NULL",
+			delete:  `DELETE FROM "series"`,
+		},
+		{
+			name: "category with children",
+			purge: func(db *gorm.DB) error {
+				_, err := NewCategoryRepository(db).Purge(context.Background(), "category-id")
+				return err
+			},
+			release: "UPDATE categories SET parent_id = NULL",
+			delete:  `DELETE FROM "categories"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, recorder := newRecordedDB(t)
+
+			if err := tt.purge(db); err != nil {
+				t.Fatalf("Purge() error = %v", err)
+			}
+
+			release := recorder.indexOf(tt.release)
+			purge := recorder.indexOf(tt.delete)
+			if release == -1 || purge == -1 {
+				t.Fatalf("statements = %q, want %q before %q", recorder.statements, tt.release, tt.delete)
+			}
+			if release > purge {
+				t.Errorf("the references were released after the delete: %q", recorder.statements)
+			}
+		})
+	}
+}
+
+func TestPurgeBumpsTheVersionOfTheReleasedRows(t *testing.T) {
+	tests := []struct {
+		name    string
+		purge   func(db *gorm.DB) error
+		lock    string
+		release string
+	}{
+		{
+			name: "publisher with books",
+			purge: func(db *gorm.DB) error {
+				_, err := NewPublisherRepository(db).Purge(context.Background(), "publisher-id")
+				return err
+			},
+			lock:    `SELECT id,publisher_id FROM "books" WHERE publisher_id IN ($1) FOR UPDATE`,
+			release: "UPDATE books SET publisher_id = NULL, version = version + 1",
+		},
+		{
+			name: "series with books",
+			purge: func(db *gorm.DB) error {
+				_, err := NewSeriesRepository(db).Purge(context.Background(), "series-id")
+				return err
+			},
+			lock:    `SELECT id,series_id,series_position FROM "books" WHERE series_id IN ($1) FOR UPDATE`,
+			release: "UPDATE books SET series_id = NULL, series_position = NULL, version = version + 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, recorder := newRecordedDB(t)
+
+			if err := tt.purge(db); err != nil {
+				t.Fatalf("Purge() error = %v", err)
+			}
+
+			lock := recorder.indexOf(tt.lock)
+			release := recorder.indexOf(tt.release)
+			if lock == -1 || release == -1 {
+				t.Fatalf("statements = %q, want %q before %q", recorder.statements, tt.lock, tt.release)
+			}
+			if lock > release {
+				t.Errorf("the released rows were read after the update: %q", recorder.statements)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// Repositories groups the repositories sharing the same database session,
+// when built from a transaction every operation joins that transaction
+type Repositories struct {
+	Books      BookRepository
+	Authors    AuthorRepository
+	Categories CategoryRepository
+	Publishers PublisherRepository
+	Series     SeriesRepository
+	AuditLogs  AuditLogRepository
+
+	db *gorm.DB
+}
+
+func NewRepositories(db *gorm.DB) *Repositories {
+	return &Repositories{
+		Books:      NewBookRepository(db),
+		Authors:    NewAuthorRepository(db),
+		Categories: NewCategoryRepository(db),
+		Publishers: NewPublisherRepository(db),
+		Series:     NewSeriesRepository(db),
+		AuditLogs:  NewAuditLogRepository(db),
+		db:         db,
+	}
+}
+
+// UnitOfWork returns a unit of work over the same session, when the
+// repositories belong to a transaction the work runs on a savepoint
+func (r *Repositories) UnitOfWork() UnitOfWork {
+	return NewUnitOfWork(r.db)
+}
+
+type UnitOfWork interface {
+	// Do runs fn inside a single transaction, it is committed when fn
+	// returns nil and rolled back when fn returns an error or panics
+	Do(ctx context.Context, fn func(repos *Repositories) error) error
+}
+
+type gormUnitOfWork struct {
+	db *gorm.DB
+}
+
+func NewUnitOfWork(db *gorm.DB) UnitOfWork {
+	return &gormUnitOfWork{db}
+}
+
+func (u *gormUnitOfWork) Do(ctx context.Context, fn func(repos *Repositories) error) error {
+	return translateError(u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(NewRepositories(tx))
+	}))
+}
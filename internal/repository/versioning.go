@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// updateVersioned saves every column of the record only when its version
+// on the database is still the one it was loaded with, bumping the version.
+// The associations are not saved
+func updateVersioned(db *gorm.DB, record interface{}, base *domain.Base) error {
+	expected := base.Version
+	base.Version = expected + 1
+
+	result := db.
+		Model(record).
+		Where("version = ?", expected).
+		Select("*").
+		Omit("id", "created_at", clause.Associations).
+		Updates(record)
+	if result.Error != nil {
+		base.Version = expected
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		base.Version = expected
+		return fmt.Errorf("%w: the record was changed by another request", domain.ErrPreconditionFailed)
+	}
+
+	return nil
+}
+
+// deleteVersioned soft deletes the record with the given ID, when version
+// is not zero the record is only deleted if it still has that version
+func deleteVersioned(db *gorm.DB, model interface{}, id string, version int64) error {
+	query := db.Where("id = ?", id)
+	if version != 0 {
+		query = query.Where("version = ?", version)
+	}
+
+	result := query.Delete(model)
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	if result.RowsAffected > 0 {
+		return nil
+	}
+
+	// Nothing was deleted, find out if the record
+	// does not exist or if it has another version
+	var count int64
+	if err := db.Model(model).Where("id = ?", id).Count(&count).Error; err != nil {
+		return translateError(err)
+	}
+	if count == 0 {
+		return translateError(gorm.ErrRecordNotFound)
+	}
+
+	return fmt.Errorf("%w: the record was changed by another request", domain.ErrPreconditionFailed)
+}
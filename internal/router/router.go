@@ -0,0 +1,141 @@
+package router
+
+import (
+	"time"
+
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/handler"
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+const apiPrefix = "/api/v1"
+
+type Handlers struct {
+	Author    *handler.AuthorHandler
+	Category  *handler.CategoryHandler
+	Book      *handler.BookHandler
+	Publisher *handler.PublisherHandler
+	Series    *handler.SeriesHandler
+	Trash     *handler.TrashHandler
+	Audit     *handler.AuditHandler
+}
+
+type Options struct {
+	// QueryTimeout is the deadline of the database queries of each request
+	QueryTimeout time.Duration
+	// AdminToken protects the administrator endpoints
+	AdminToken string
+}
+
+func New(handlers Handlers, options Options) *gin.Engine {
+	r := gin.Default()
+
+	api := r.Group(apiPrefix)
+	api.Use(middleware.Timeout(options.QueryTimeout), middleware.Actor(options.AdminToken))
+
+	authors := api.Group("/authors")
+	{
+		authors.POST("", handlers.Author.CreateAuthor)
+		authors.GET("", handlers.Author.FindAllAuthors)
+		authors.GET("/:id", handlers.Author.FindAuthorByID)
+		authors.GET("/name/:name", handlers.Author.FindAuthorByName)
+		authors.PUT("/:id", handlers.Author.UpdateAuthor)
+		authors.PATCH("/:id", handlers.Author.PatchAuthor)
+		authors.DELETE("/:id", handlers.Author.DeleteAuthorByID)
+		authors.POST("/:id/merge", handlers.Author.MergeAuthor)
+		authors.GET("/:id/aliases", handlers.Author.FindAuthorAliases)
+		authors.POST("/:id/aliases", handlers.Author.AddAuthorAlias)
+		authors.DELETE("/:id/aliases/:alias_id", handlers.Author.RemoveAuthorAlias)
+	}
+
+	categories := api.Group("/categories")
+	{
+		categories.POST("", handlers.Category.CreateCategory)
+		categories.GET("", handlers.Category.FindAllCategories)
+		categories.GET("/tree", handlers.Category.FindCategoryTree)
+		categories.GET("/:id", handlers.Category.FindCategoryByID)
+		categories.GET("/:id/subtree", handlers.Category.FindCategorySubtree)
+		categories.GET("/name/:name", handlers.Category.FindCategoryByName)
+		categories.PUT("/:id", handlers.Category.UpdateCategory)
+		categories.PATCH("/:id", handlers.Category.PatchCategory)
+		categories.DELETE("/:id", handlers.Category.DeleteCategoryByID)
+		categories.POST("/:id/merge", handlers.Category.MergeCategory)
+	}
+
+	publishers := api.Group("/publishers")
+	{
+		publishers.POST("", handlers.Publisher.CreatePublisher)
+		publishers.GET("", handlers.Publisher.FindAllPublishers)
+		publishers.GET("/:id", handlers.Publisher.FindPublisherByID)
+		publishers.GET("/name/:name", handlers.Publisher.FindPublisherByName)
+		publishers.PUT("/:id", handlers.Publisher.UpdatePublisher)
+		publishers.PATCH("/:id", handlers.Publisher.PatchPublisher)
+		publishers.DELETE("/:id", handlers.Publisher.DeletePublisherByID)
+	}
+
+	series := api.Group("/series")
+	{
+		series.POST("", handlers.Series.CreateSeries)
+		series.GET("", handlers.Series.FindAllSeries)
+		series.GET("/:id", handlers.Series.FindSeriesByID)
+		series.GET("/:id/volumes", handlers.Book.FindSeriesVolumes)
+		series.GET("/name/:name", handlers.Series.FindSeriesByName)
+		series.PUT("/:id", handlers.Series.UpdateSeries)
+		series.PATCH("/:id", handlers.Series.PatchSeries)
+		series.DELETE("/:id", handlers.Series.DeleteSeriesByID)
+	}
+
+	books := api.Group("/books")
+	{
+		books.POST("", handlers.Book.CreateBook)
+		books.GET("", handlers.Book.FindAllBooks)
+		books.GET("/search", handlers.Book.SearchBooks)
+		books.GET("/:id", handlers.Book.FindBookByID)
+		books.GET("/title/:title", handlers.Book.FindBookByTitle)
+		books.GET("/isbn/:isbn", handlers.Book.FindBookByISBN)
+		books.PUT("/:id", handlers.Book.UpdateBook)
+		books.PATCH("/:id", handlers.Book.PatchBook)
+		books.DELETE("/:id", handlers.Book.DeleteBookByID)
+	}
+
+	// The exports stream the whole catalogue and the imports write a whole
+	// file in a single transaction, so they are not bound to the query
+	// timeout, they stop when the client disconnects
+	bulk := r.Group(apiPrefix, middleware.Actor(options.AdminToken))
+	{
+		bulk.GET("/books/export", handlers.Book.ExportBooks)
+		bulk.POST("/books/import", handlers.Book.ImportBooks)
+	}
+
+	// Soft deleted records, only available to the administrators
+	trash := api.Group("/trash", middleware.RequireAdmin(options.AdminToken))
+	{
+		trash.GET("/books", handlers.Trash.FindDeletedBooks)
+		trash.POST("/books/:id/restore", handlers.Trash.RestoreBook)
+		trash.DELETE("/books/:id", handlers.Trash.PurgeBook)
+
+		trash.GET("/authors", handlers.Trash.FindDeletedAuthors)
+		trash.POST("/authors/:id/restore", handlers.Trash.RestoreAuthor)
+		trash.DELETE("/authors/:id", handlers.Trash.PurgeAuthor)
+
+		trash.GET("/categories", handlers.Trash.FindDeletedCategories)
+		trash.POST("/categories/:id/restore", handlers.Trash.RestoreCategory)
+		trash.DELETE("/categories/:id", handlers.Trash.PurgeCategory)
+
+		trash.GET("/publishers", handlers.Trash.FindDeletedPublishers)
+		trash.POST("/publishers/:id/restore", handlers.Trash.RestorePublisher)
+		trash.DELETE("/publishers/:id", handlers.Trash.PurgePublisher)
+
+		trash.GET("/series", handlers.Trash.FindDeletedSeries)
+		trash.POST("/series/:id/restore", handlers.Trash.RestoreSeries)
+		trash.DELETE("/series/:id", handlers.Trash.PurgeSeries)
+	}
+
+	// Log of the changes made to the records, only available to the administrators
+	audit := api.Group("/audit", middleware.RequireAdmin(options.AdminToken))
+	{
+		audit.GET("", handlers.Audit.FindAuditLogs)
+	}
+
+	return r
+}
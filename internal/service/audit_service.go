@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/domain"
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/repository"
+)
+
+const (
+	auditEntityBook      = "book"
+	auditEntityAuthor    = "author"
+	auditEntityCategory  = "category"
+	auditEntityPublisher = "publisher"
+	auditEntitySeries    = "series"
+)
+
+const (
+	// AnonymousActor is recorded on the audit log when the request has no verified actor
+	AnonymousActor = "anonymous"
+	// AdminActor is recorded on the audit log for the requests sending the administrator token
+	AdminActor = "admin"
+)
+
+type actorContextKey struct{}
+
+type claimedActorContextKey struct{}
+
+// WithActor returns a copy of ctx carrying who is making the changes,
+// the actor must be verified (a credential or the process itself)
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorContextKey{}, actor)
+}
+
+// ActorFromContext returns the actor set by WithActor, or AnonymousActor
+func ActorFromContext(ctx context.Context) string {
+	actor, ok := ctx.Value(actorContextKey{}).(string)
+	if !ok || actor == "" {
+		return AnonymousActor
+	}
+
+	return actor
+}
+
+// WithClaimedActor returns a copy of ctx carrying who the client claims to be.
+// Nothing verifies the claim, so it is recorded apart from the actor
+func WithClaimedActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, claimedActorContextKey{}, actor)
+}
+
+// ClaimedActorFromContext returns the actor set by WithClaimedActor, or nil
+func ClaimedActorFromContext(ctx context.Context) *string {
+	actor, ok := ctx.Value(claimedActorContextKey{}).(string)
+	if !ok || actor == "" {
+		return nil
+	}
+
+	return &actor
+}
+
+type AuditService interface {
+	FindAuditLogs(ctx context.Context, filter repository.AuditLogFilter, page repository.PageRequest) ([]*domain.AuditLog, *repository.PageInfo, error)
+}
+
+type auditService struct {
+	auditLogRepo repository.AuditLogRepository
+}
+
+func NewAuditService(auditLogRepo repository.AuditLogRepository) AuditService {
+	return &auditService{auditLogRepo}
+}
+
+func (s *auditService) FindAuditLogs(ctx context.Context, filter repository.AuditLogFilter, page repository.PageRequest) ([]*domain.AuditLog, *repository.PageInfo, error) {
+	switch filter.EntityType {
+	case "", auditEntityBook, auditEntityAuthor, auditEntityCategory, auditEntityPublisher, auditEntitySeries:
+	default:
+		return nil, nil, domain.NewValidationError("entity_type", fmt.Sprintf(
+			"must be one of %q, %q, %q, %q or %q",
+			auditEntityBook, auditEntityAuthor, auditEntityCategory, auditEntityPublisher, auditEntitySeries,
+		))
+	}
+
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, nil, domain.NewValidationError("from", "must be before to")
+	}
+
+	return s.auditLogRepo.FindAll(ctx, filter, page)
+}
+
+// recordAudit writes an entry on the audit log with the fields that differ
+// between the before and after snapshots, a nil snapshot stands for a
+// record that did not exist before (create) or does not exist after (delete)
+func recordAudit(
+	ctx context.Context,
+	repos *repository.Repositories,
+	entityType, entityID string,
+	action domain.AuditAction,
+	before, after map[string]interface{},
+) error {
+	auditLog := &domain.AuditLog{
+		EntityType:   entityType,
+		EntityID:     entityID,
+		Action:       action,
+		Actor:        ActorFromContext(ctx),
+		ClaimedActor: ClaimedActorFromContext(ctx),
+		Changes:      diffSnapshots(before, after),
+	}
+
+	if err := repos.AuditLogs.Create(ctx, auditLog); err != nil {
+		return fmt.Errorf("error while trying to record the audit log: %w", err)
+	}
+
+	return nil
+}
+
+func diffSnapshots(before, after map[string]interface{}) domain.AuditChanges {
+	changes := domain.AuditChanges{}
+
+	for field, value := range before {
+		if !reflect.DeepEqual(value, after[field]) {
+			changes[field] = domain.AuditChange{Before: value, After: after[field]}
+		}
+	}
+
+	for field, value := range after {
+		if _, ok := before[field]; !ok {
+			changes[field] = domain.AuditChange{After: value}
+		}
+	}
+
+	return changes
+}
+
+// releasedEntities maps the tables of the rows released by a purge to their entity type
+var releasedEntities = map[string]string{
+	"books":      auditEntityBook,
+	"categories": auditEntityCategory,
+}
+
+// auditReleased records an update for every row that had its references to a
+// purged record set to NULL, as the purge changed the row without going through its service
+func auditReleased(ctx context.Context, repos *repository.Repositories, released []repository.ReleasedReference) error {
+	for _, reference := range released {
+		entityType, ok := releasedEntities[reference.Table]
+		if !ok {
+			return fmt.Errorf("%w: no audit entity for the table %q", domain.ErrInternal, reference.Table)
+		}
+
+		after := make(map[string]interface{}, len(reference.Before))
+		for column := range reference.Before {
+			after[column] = nil
+		}
+
+		if err := recordAudit(ctx, repos, entityType, reference.ID, domain.AuditActionUpdate, reference.Before, after); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// mergedSnapshot is the state of a record merged into another one
+func mergedSnapshot(targetID string) map[string]interface{} {
+	return map[string]interface{}{
+		"merged_into": targetID,
+	}
+}
+
+func authorSnapshot(author *domain.Author) map[string]interface{} {
+	return map[string]interface{}{
+		"name": author.Name,
+	}
+}
+
+func authorAliasesSnapshot(aliases []*domain.AuthorAlias) map[string]interface{} {
+	names := make([]string, 0, len(aliases))
+	for _, alias := range aliases {
+		names = append(names, alias.Name)
+	}
+
+	return map[string]interface{}{
+		"aliases": names,
+	}
+}
+
+func categorySnapshot(category *domain.Category) map[string]interface{} {
+	return map[string]interface{}{
+		"name":      category.Name,
+		"parent_id": category.ParentID,
+	}
+}
+
+func publisherSnapshot(publisher *domain.Publisher) map[string]interface{} {
+	return map[string]interface{}{
+		"name": publisher.Name,
+	}
+}
+
+func seriesSnapshot(series *domain.Series) map[string]interface{} {
+	return map[string]interface{}{
+		"name": series.Name,
+	}
+}
+
+func bookSnapshot(book *domain.Book) map[string]interface{} {
+	authorIDs := make([]string, 0, len(book.Authors))
+	for _, author := range book.Authors {
+		authorIDs = append(authorIDs, author.ID)
+	}
+	sort.Strings(authorIDs)
+
+	// Only the authors credited under an alias are kept
+	authorCredits := make(map[string]string)
+	for _, credit := range book.AuthorCredits {
+		if credit.CreditedAs != nil {
+			authorCredits[credit.AuthorID] = *credit.CreditedAs
+		}
+	}
+
+	categoryIDs := make([]string, 0, len(book.Categories))
+	for _, category := range book.Categories {
+		categoryIDs = append(categoryIDs, category.ID)
+	}
+	sort.Strings(categoryIDs)
+
+	return map[string]interface{}{
+		"title":           book.Title,
+		"synopsis":        book.Synopsis,
+		"isbn":            book.ISBN,
+		"publisher_id":    book.PublisherID,
+		"series_id":       book.SeriesID,
+		"series_position": book.SeriesPosition,
+		"author_ids":      authorIDs,
+		"author_credits":  authorCredits,
+		"category_ids":    categoryIDs,
+	}
+}
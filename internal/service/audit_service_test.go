@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/domain"
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/repository"
+)
+
+func TestAuditReleased(t *testing.T) {
+	auditLogs := &fakeAuditLogRepository{}
+	repos := &repository.Repositories{AuditLogs: auditLogs}
+
+	released := []repository.ReleasedReference{
+		{Table: "books", ID: "book-id", Before: map[string]interface{}{"series_id": "series-id", "series_position": int64(2)}},
+		{Table: "categories", ID: "category-id", Before: map[string]interface{}{"parent_id": "parent-id"}},
+	}
+
+	if err := auditReleased(context.Background(), repos, released); err != nil {
+		t.Fatalf("auditReleased() error = %v", err)
+	}
+
+	if len(auditLogs.logs) != len(released) {
+		t.Fatalf("audit logs = %d, want %d", len(auditLogs.logs), len(released))
+	}
+
+	wantEntities := []string{auditEntityBook, auditEntityCategory}
+	for i, auditLog := range auditLogs.logs {
+		if auditLog.EntityType != wantEntities[i] || auditLog.EntityID != released[i].ID || auditLog.Action != domain.AuditActionUpdate {
+			t.Errorf("audit log %d = {%q, %q, %q}, want {%q, %q, %q}",
+				i, auditLog.EntityType, auditLog.EntityID, auditLog.Action,
+				wantEntities[i], released[i].ID, domain.AuditActionUpdate,
+			)
+		}
+
+		for column, value := range released[i].Before {
+			change, ok := auditLog.Changes[column]
+			if !ok || change.Before != value || change.After != nil {
+				t.Errorf("audit log %d change %s = %+v, want {%v, <nil>}", i, column, change, value)
+			}
+		}
+	}
+}
+
+func TestAuditReleasedUnknownTable(t *testing.T) {
+	repos := &repository.Repositories{AuditLogs: &fakeAuditLogRepository{}}
+	released := []repository.ReleasedReference{{Table: "unknown", ID: "id"}}
+
+	if err := auditReleased(context.Background(), repos, released); err == nil {
+		t.Errorf("auditReleased() error = nil, want an error")
+	}
+}
@@ -1,6 +1,8 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"fmt"
 
 	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/domain"
@@ -8,91 +10,329 @@ import (
 )
 
 type AuthorService interface {
-	CreateAuthor(author *domain.Author) error
-	FindAuthorByID(id string) (*domain.Author, error)
-	FindAuthorByName(name string) (*domain.Author, error)
-	FindAllAuthors() ([]*domain.Author, error)
-	UpdateAuthor(author *domain.Author) error
-	DeleteAuthorByID(id string) error
+	CreateAuthor(ctx context.Context, author *domain.Author) error
+	FindAuthorByID(ctx context.Context, id string) (*domain.Author, error)
+	FindAuthorByName(ctx context.Context, name string) (*domain.Author, error)
+	FindAllAuthors(ctx context.Context, page repository.PageRequest) ([]*domain.Author, *repository.PageInfo, error)
+	UpdateAuthor(ctx context.Context, author *domain.Author) error
+	DeleteAuthorByID(ctx context.Context, id string, version int64) error
+	FindDeletedAuthors(ctx context.Context, page repository.PageRequest) ([]*domain.Author, *repository.PageInfo, error)
+	RestoreAuthorByID(ctx context.Context, id string) error
+	PurgeAuthorByID(ctx context.Context, id string) error
+	FindAuthorAliases(ctx context.Context, authorID string) ([]*domain.AuthorAlias, error)
+	AddAuthorAlias(ctx context.Context, alias *domain.AuthorAlias) error
+	RemoveAuthorAlias(ctx context.Context, authorID, aliasID string) error
+	MergeAuthors(ctx context.Context, sourceID, targetID string, version int64) (*domain.Author, error)
 }
 
 type authorService struct {
 	authorRepo repository.AuthorRepository
+	uow        repository.UnitOfWork
 }
 
-func NewAuthorService(authorRepo repository.AuthorRepository) AuthorService {
-	return &authorService{authorRepo}
+// NewAuthorService builds the author service, the writes run inside
+// a unit of work so they are recorded on the audit log atomically
+func NewAuthorService(authorRepo repository.AuthorRepository, uow repository.UnitOfWork) AuthorService {
+	return &authorService{authorRepo, uow}
 }
 
-func (s *authorService) CreateAuthor(author *domain.Author) error {
+func (s *authorService) CreateAuthor(ctx context.Context, author *domain.Author) error {
+	author.Name = domain.NormalizeName(author.Name)
 	authorName := author.Name
 
 	if authorName == "" {
-		return fmt.Errorf("author name is required")
+		return domain.NewValidationError("name", "is required")
 	}
 
 	// Check if the author already exists
-	if _, err := s.FindAuthorByName(authorName); err == nil {
-		return fmt.Errorf("author already exists")
+	_, err := s.FindAuthorByName(ctx, authorName)
+	if err == nil {
+		return fmt.Errorf("%w: author already exists", domain.ErrConflict)
 	}
+	if !errors.Is(err, domain.ErrNotFound) {
+		return fmt.Errorf("error while trying to find the author by name: %w", err)
+	}
+
+	return s.uow.Do(ctx, func(repos *repository.Repositories) error {
+		if err := repos.Authors.Create(ctx, author); err != nil {
+			return err
+		}
 
-	return s.authorRepo.Create(author)
+		return recordAudit(ctx, repos, auditEntityAuthor, author.ID, domain.AuditActionCreate, nil, authorSnapshot(author))
+	})
 }
 
-func (s *authorService) FindAuthorByID(id string) (*domain.Author, error) {
+func (s *authorService) FindAuthorByID(ctx context.Context, id string) (*domain.Author, error) {
 	if id == "" {
-		return nil, fmt.Errorf("author ID is required")
+		return nil, domain.NewValidationError("id", "is required")
 	}
 
-	return s.authorRepo.FindByID(id)
+	return s.authorRepo.FindByID(ctx, id)
 }
 
-func (s *authorService) FindAuthorByName(name string) (*domain.Author, error) {
+func (s *authorService) FindAuthorByName(ctx context.Context, name string) (*domain.Author, error) {
+	name = domain.NormalizeName(name)
 	if name == "" {
-		return nil, fmt.Errorf("author name is required")
+		return nil, domain.NewValidationError("name", "is required")
 	}
 
-	return s.authorRepo.FindByName(name)
+	return s.authorRepo.FindByName(ctx, name)
 }
 
-func (s *authorService) FindAllAuthors() ([]*domain.Author, error) {
-	return s.authorRepo.FindAll()
+func (s *authorService) FindAllAuthors(ctx context.Context, page repository.PageRequest) ([]*domain.Author, *repository.PageInfo, error) {
+	return s.authorRepo.FindAll(ctx, page)
 }
 
-func (s *authorService) UpdateAuthor(author *domain.Author) error {
+func (s *authorService) UpdateAuthor(ctx context.Context, author *domain.Author) error {
 	authorID := author.ID
-	newAuthorName := author.Name
+	newAuthorName := domain.NormalizeName(author.Name)
 
 	if authorID == "" {
-		return fmt.Errorf("author ID is required")
+		return domain.NewValidationError("id", "is required")
 	}
 	if newAuthorName == "" {
-		return fmt.Errorf("author name is required")
+		return domain.NewValidationError("name", "is required")
 	}
 
-	authorOnDB, err := s.FindAuthorByID(authorID)
+	authorOnDB, err := s.FindAuthorByID(ctx, authorID)
 	if err != nil {
-		return fmt.Errorf("error while trying to find the author by ID: %v", err)
+		return fmt.Errorf("error while trying to find the author by ID: %w", err)
 	}
 
+	// The version sent by the client (if any) must be the current one
+	if err := checkVersion(author.Version, authorOnDB.Version); err != nil {
+		return err
+	}
+
+	before := authorSnapshot(authorOnDB)
+
 	var isNameChanged bool
 	if newAuthorName != authorOnDB.Name {
+		// The name may only differ on the case or the accents,
+		// so the one found must be the author itself
+		namesake, err := s.FindAuthorByName(ctx, newAuthorName)
+		if err == nil && namesake.ID != authorID {
+			return fmt.Errorf("%w: author already exists", domain.ErrConflict)
+		}
+		if err != nil && !errors.Is(err, domain.ErrNotFound) {
+			return fmt.Errorf("error while trying to find the author by name: %w", err)
+		}
+
 		authorOnDB.Name = newAuthorName
 		isNameChanged = true
 	}
 
 	if !isNameChanged {
 		// If the name is not changed, there is no need to update the category
+		author.Version = authorOnDB.Version
 		return nil
 	}
 
-	return s.authorRepo.Update(authorOnDB)
+	err = s.uow.Do(ctx, func(repos *repository.Repositories) error {
+		if err := repos.Authors.Update(ctx, authorOnDB); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, repos, auditEntityAuthor, authorOnDB.ID, domain.AuditActionUpdate, before, authorSnapshot(authorOnDB))
+	})
+	if err != nil {
+		return err
+	}
+
+	author.Version = authorOnDB.Version
+
+	return nil
 }
 
-func (s *authorService) DeleteAuthorByID(id string) error {
+// DeleteAuthorByID deletes the author, when version is not zero the author
+// is only deleted if it was not changed since that version
+func (s *authorService) DeleteAuthorByID(ctx context.Context, id string, version int64) error {
 	if id == "" {
-		return fmt.Errorf("author ID is required")
+		return domain.NewValidationError("id", "is required")
+	}
+
+	return s.uow.Do(ctx, func(repos *repository.Repositories) error {
+		// The ID of a merged author resolves to the author it was merged into,
+		// which is the one deleted, the same way it is the one returned by the lookups
+		authorOnDB, err := repos.Authors.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := repos.Authors.Delete(ctx, authorOnDB.ID, version); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, repos, auditEntityAuthor, authorOnDB.ID, domain.AuditActionDelete, authorSnapshot(authorOnDB), nil)
+	})
+}
+
+func (s *authorService) FindDeletedAuthors(ctx context.Context, page repository.PageRequest) ([]*domain.Author, *repository.PageInfo, error) {
+	return s.authorRepo.FindDeleted(ctx, page)
+}
+
+// RestoreAuthorByID brings back a soft deleted author
+func (s *authorService) RestoreAuthorByID(ctx context.Context, id string) error {
+	if id == "" {
+		return domain.NewValidationError("id", "is required")
+	}
+
+	return s.uow.Do(ctx, func(repos *repository.Repositories) error {
+		if err := repos.Authors.Restore(ctx, id); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, repos, auditEntityAuthor, id, domain.AuditActionRestore, nil, nil)
+	})
+}
+
+// PurgeAuthorByID permanently deletes a soft deleted author
+func (s *authorService) PurgeAuthorByID(ctx context.Context, id string) error {
+	if id == "" {
+		return domain.NewValidationError("id", "is required")
+	}
+
+	return s.uow.Do(ctx, func(repos *repository.Repositories) error {
+		released, err := repos.Authors.Purge(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := auditReleased(ctx, repos, released); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, repos, auditEntityAuthor, id, domain.AuditActionPurge, nil, nil)
+	})
+}
+
+// FindAuthorAliases lists the aliases of the author, the ID of a
+// merged author lists the aliases of the author it was merged into
+func (s *authorService) FindAuthorAliases(ctx context.Context, authorID string) ([]*domain.AuthorAlias, error) {
+	author, err := s.FindAuthorByID(ctx, authorID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.authorRepo.FindAliases(ctx, author.ID)
+}
+
+// AddAuthorAlias adds another name to the author, the alias can not
+// be the name or the alias of any author (including this one)
+func (s *authorService) AddAuthorAlias(ctx context.Context, alias *domain.AuthorAlias) error {
+	alias.Name = domain.NormalizeName(alias.Name)
+
+	if alias.AuthorID == "" {
+		return domain.NewValidationError("author_id", "is required")
+	}
+	if alias.Name == "" {
+		return domain.NewValidationError("name", "is required")
+	}
+
+	return s.uow.Do(ctx, func(repos *repository.Repositories) error {
+		// The ID of a merged author resolves to the author it was merged into
+		author, err := repos.Authors.FindByID(ctx, alias.AuthorID)
+		if err != nil {
+			return err
+		}
+		alias.AuthorID = author.ID
+
+		_, err = repos.Authors.FindByName(ctx, alias.Name)
+		if err == nil {
+			return fmt.Errorf("%w: an author is already known by this name", domain.ErrConflict)
+		}
+		if !errors.Is(err, domain.ErrNotFound) {
+			return fmt.Errorf("error while trying to find the author by name: %w", err)
+		}
+
+		return s.changeAliases(ctx, repos, alias.AuthorID, func() error {
+			return repos.Authors.CreateAlias(ctx, alias)
+		})
+	})
+}
+
+func (s *authorService) RemoveAuthorAlias(ctx context.Context, authorID, aliasID string) error {
+	if authorID == "" {
+		return domain.NewValidationError("author_id", "is required")
+	}
+	if aliasID == "" {
+		return domain.NewValidationError("alias_id", "is required")
+	}
+
+	return s.uow.Do(ctx, func(repos *repository.Repositories) error {
+		// The ID of a merged author resolves to the author it was merged into
+		author, err := repos.Authors.FindByID(ctx, authorID)
+		if err != nil {
+			return err
+		}
+
+		return s.changeAliases(ctx, repos, author.ID, func() error {
+			return repos.Authors.DeleteAlias(ctx, author.ID, aliasID)
+		})
+	})
+}
+
+// changeAliases runs change and records the aliases of the
+// author before and after it as an update of the author
+func (s *authorService) changeAliases(ctx context.Context, repos *repository.Repositories, authorID string, change func() error) error {
+	before, err := repos.Authors.FindAliases(ctx, authorID)
+	if err != nil {
+		return err
+	}
+
+	if err := change(); err != nil {
+		return err
+	}
+
+	after, err := repos.Authors.FindAliases(ctx, authorID)
+	if err != nil {
+		return err
+	}
+
+	return recordAudit(ctx, repos, auditEntityAuthor, authorID, domain.AuditActionUpdate, authorAliasesSnapshot(before), authorAliasesSnapshot(after))
+}
+
+// MergeAuthors merges the source author into the target one, the books and
+// aliases of the source move to the target and the source is deleted. When
+// version is not zero the source is only merged if it still has that version
+func (s *authorService) MergeAuthors(ctx context.Context, sourceID, targetID string, version int64) (*domain.Author, error) {
+	if sourceID == "" {
+		return nil, domain.NewValidationError("id", "is required")
+	}
+	if targetID == "" {
+		return nil, domain.NewValidationError("target_id", "is required")
+	}
+
+	var target *domain.Author
+	err := s.uow.Do(ctx, func(repos *repository.Repositories) error {
+		source, err := repos.Authors.FindByID(ctx, sourceID)
+		if err != nil {
+			return err
+		}
+		if source.ID != sourceID {
+			return fmt.Errorf("%w: the author was already merged into another one", domain.ErrConflict)
+		}
+
+		target, err = repos.Authors.FindByID(ctx, targetID)
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.NewValidationError("target_id", "does not match any author")
+		}
+		if err != nil {
+			return err
+		}
+		if target.ID == source.ID {
+			return domain.NewValidationError("target_id", "must be another author")
+		}
+
+		if err := repos.Authors.Merge(ctx, source.ID, target.ID, version); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, repos, auditEntityAuthor, source.ID, domain.AuditActionMerge, authorSnapshot(source), mergedSnapshot(target.ID))
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return s.authorRepo.Delete(id)
+	return target, nil
 }
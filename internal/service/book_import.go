@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/bookcsv"
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/domain"
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/repository"
+)
+
+// BookImportResult is the outcome of a single CSV row,
+// Err is set when the row was not imported
+type BookImportResult struct {
+	Line    int
+	Book    *domain.Book
+	Changes *BookChanges
+	Err     error
+}
+
+type BookImportReport struct {
+	DryRun   bool
+	Imported int
+	Failed   int
+	Results  []BookImportResult
+}
+
+// errDryRun rolls back the import transaction once every row was tried
+var errDryRun = errors.New("dry run")
+
+// ImportBooks creates a book for each row of the CSV (see the bookcsv
+// package for the layout). Every row is created on its own savepoint, so a
+// failed row does not stop the others, and on a dry run the whole import is
+// rolled back after the report is built
+func (s *bookService) ImportBooks(ctx context.Context, r io.Reader, dryRun bool) (*BookImportReport, error) {
+	reader, err := bookcsv.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+
+	report := &BookImportReport{DryRun: dryRun}
+	err = s.uow.Do(ctx, func(repos *repository.Repositories) error {
+		for {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			record, err := reader.Read()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return fmt.Errorf("error in book_services while reading the CSV: %w", err)
+			}
+
+			result := BookImportResult{
+				Line: record.Line,
+				Book: record.Book,
+				Err:  record.Err,
+			}
+
+			if result.Err == nil {
+				result.Changes, result.Err = s.importBook(ctx, repos, record.Book)
+			}
+
+			if result.Err != nil {
+				result.Changes = nil
+				report.Failed++
+			} else {
+				report.Imported++
+			}
+
+			report.Results = append(report.Results, result)
+		}
+
+		if dryRun {
+			return errDryRun
+		}
+
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// importBook validates and creates a single imported book on a savepoint
+func (s *bookService) importBook(ctx context.Context, repos *repository.Repositories, book *domain.Book) (*BookChanges, error) {
+	ok, err := s.validateBook(book)
+	if !ok {
+		return nil, fmt.Errorf("invalid book: %w", err)
+	}
+
+	var changes *BookChanges
+	err = repos.UnitOfWork().Do(ctx, func(repos *repository.Repositories) error {
+		changes, err = s.createBook(ctx, repos, book)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
@@ -1,217 +1,915 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
+	"strings"
 
 	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/domain"
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/isbn"
 	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/repository"
-	"gorm.io/gorm"
 )
 
 type BookService interface {
-	CreateBook(book *domain.Book) error
-	FindBookByID(id string) (*domain.Book, error)
-	FindBookByTitle(title string) (*domain.Book, error)
-	FindAllBooks() ([]*domain.Book, error)
-	UpdateBook(book *domain.Book) error
-	DeleteBookByID(id string) error
+	CreateBook(ctx context.Context, book *domain.Book) (*BookChanges, error)
+	ImportBooks(ctx context.Context, r io.Reader, dryRun bool) (*BookImportReport, error)
+	FindBookByID(ctx context.Context, id string) (*domain.Book, error)
+	FindBookByTitle(ctx context.Context, title string) (*domain.Book, error)
+	FindBookByISBN(ctx context.Context, value string) (*domain.Book, error)
+	FindSeriesVolumes(ctx context.Context, seriesID string) ([]*domain.Book, error)
+	FindVolumeNavigation(ctx context.Context, book *domain.Book) (*VolumeNavigation, error)
+	FindAllBooks(ctx context.Context, filter repository.BookFilter, page repository.PageRequest) ([]*domain.Book, *repository.PageInfo, error)
+	ExportBooks(ctx context.Context, filter repository.BookFilter, fn func(books []*domain.Book) error) error
+	SearchBooks(ctx context.Context, term string, limit, offset int) ([]*repository.BookSearchResult, error)
+	UpdateBook(ctx context.Context, book *domain.Book, mode repository.AssociationMode) (*BookChanges, error)
+	DeleteBookByID(ctx context.Context, id string, version int64) error
+	FindDeletedBooks(ctx context.Context, page repository.PageRequest) ([]*domain.Book, *repository.PageInfo, error)
+	RestoreBookByID(ctx context.Context, id string) error
+	PurgeBookByID(ctx context.Context, id string) error
+}
+
+// BookChanges reports how the authors, categories, publisher and series sent
+// with a book were resolved (the ones created on the write and the existing ones
+// reused) and which authors and categories were linked to or unlinked from the book
+type BookChanges struct {
+	CreatedAuthors     []domain.Author
+	ReusedAuthors      []domain.Author
+	LinkedAuthors      []domain.Author
+	UnlinkedAuthors    []domain.Author
+	CreatedCategories  []domain.Category
+	ReusedCategories   []domain.Category
+	LinkedCategories   []domain.Category
+	UnlinkedCategories []domain.Category
+	CreatedPublisher   *domain.Publisher
+	ReusedPublisher    *domain.Publisher
+	CreatedSeries      *domain.Series
+	ReusedSeries       *domain.Series
+}
+
+// VolumeNavigation holds the volumes around a book in its series,
+// they are nil when the book is the first or the last one
+type VolumeNavigation struct {
+	Previous *domain.Book
+	Next     *domain.Book
 }
 
 type bookService struct {
-	bookRepo     repository.BookRepository
-	categoryRepo repository.CategoryRepository
-	authorRepo   repository.AuthorRepository
+	bookRepo repository.BookRepository
+	uow      repository.UnitOfWork
 }
 
+// NewBookService builds the book service, the writes touching more than one
+// entity (books, categories and authors) run inside a single unit of work
 func NewBookService(
 	bookRepo repository.BookRepository,
-	categoryRepo repository.CategoryRepository,
-	authorRepo repository.AuthorRepository,
+	uow repository.UnitOfWork,
 ) BookService {
-	return &bookService{bookRepo, categoryRepo, authorRepo}
+	return &bookService{bookRepo, uow}
 }
 
-func (s *bookService) CreateBook(book *domain.Book) error {
+func (s *bookService) CreateBook(ctx context.Context, book *domain.Book) (*BookChanges, error) {
 	ok, err := s.validateBook(book)
 	if !ok {
-		return fmt.Errorf("invalid book: %v", err)
+		return nil, fmt.Errorf("invalid book: %w", err)
 	}
 
-	book, _, err = s.handleCategory(book)
+	var changes *BookChanges
+	err = s.uow.Do(ctx, func(repos *repository.Repositories) error {
+		changes, err = s.createBook(ctx, repos, book)
+		return err
+	})
 	if err != nil {
-		return fmt.Errorf("error in book_services while handling category: %v", err)
+		return nil, err
 	}
 
-	book, _, err = s.handleAuthor(book)
-	if err != nil {
-		return fmt.Errorf("error in book_services while handling author: %v", err)
+	return changes, nil
+}
+
+// createBook resolves the categories and authors of an already
+// validated book and creates it using the given repositories
+func (s *bookService) createBook(ctx context.Context, repos *repository.Repositories, book *domain.Book) (*BookChanges, error) {
+	changes := &BookChanges{}
+
+	if err := s.checkISBNAvailable(ctx, repos, book.ISBN, ""); err != nil {
+		return nil, err
+	}
+
+	if err := s.handleCategory(ctx, repos, book, changes, true); err != nil {
+		return nil, fmt.Errorf("error in book_services while handling category: %w", err)
+	}
+
+	if err := s.handleAuthor(ctx, repos, book, nil, changes, true); err != nil {
+		return nil, fmt.Errorf("error in book_services while handling author: %w", err)
+	}
+
+	if err := s.handlePublisher(ctx, repos, book, changes); err != nil {
+		return nil, fmt.Errorf("error in book_services while handling publisher: %w", err)
+	}
+
+	if err := s.handleSeries(ctx, repos, book, changes); err != nil {
+		return nil, fmt.Errorf("error in book_services while handling series: %w", err)
+	}
+
+	if err := s.checkSeriesPositionAvailable(ctx, repos, book.SeriesID, book.SeriesPosition, ""); err != nil {
+		return nil, err
 	}
 
-	return s.bookRepo.Create(book)
+	if err := repos.Books.Create(ctx, book); err != nil {
+		return nil, err
+	}
+
+	if err := repos.Books.UpdateAuthorCredits(ctx, book.ID, changedCredits(nil, book.AuthorCredits)); err != nil {
+		return nil, fmt.Errorf("error in book_services while trying to credit the book authors: %w", err)
+	}
+
+	if err := recordAudit(ctx, repos, auditEntityBook, book.ID, domain.AuditActionCreate, nil, bookSnapshot(book)); err != nil {
+		return nil, err
+	}
+
+	changes.LinkedAuthors = book.Authors
+	changes.LinkedCategories = book.Categories
+
+	return changes, nil
 }
 
 func (s *bookService) validateBook(book *domain.Book) (bool, error) {
 	if book.Title == "" {
-		return false, fmt.Errorf("title is required")
+		return false, domain.NewValidationError("title", "is required")
 	}
 
 	if book.Synopsis == "" {
-		return false, fmt.Errorf("synopsis is required")
+		return false, domain.NewValidationError("synopsis", "is required")
+	}
+
+	// Both ISBN forms are accepted but only the ISBN-13 one is stored
+	if book.ISBN != nil {
+		normalized, err := isbn.Normalize(*book.ISBN)
+		if err != nil {
+			return false, domain.NewValidationError("isbn", err.Error())
+		}
+		book.ISBN = &normalized
 	}
 
 	for _, category := range book.Categories {
-		if category.Name == "" {
-			return false, fmt.Errorf("category name is required")
+		if category.ID == "" && domain.NormalizeName(category.Name) == "" {
+			return false, domain.NewValidationError("categories", "must have either an ID or a name")
 		}
 	}
 
 	for _, author := range book.Authors {
-		if author.Name == "" {
-			return false, fmt.Errorf("author name is required")
+		if author.ID == "" && domain.NormalizeName(author.Name) == "" {
+			return false, domain.NewValidationError("authors", "must have either an ID or a name")
 		}
 	}
 
+	if book.Publisher != nil && book.Publisher.ID == "" && domain.NormalizeName(book.Publisher.Name) == "" {
+		return false, domain.NewValidationError("publisher", "must have either an ID or a name")
+	}
+
+	hasSeries := book.Series != nil || book.SeriesID != nil
+	if book.Series != nil && book.Series.ID == "" && domain.NormalizeName(book.Series.Name) == "" {
+		return false, domain.NewValidationError("series", "must have either an ID or a name")
+	}
+	if hasSeries && (book.SeriesPosition == nil || *book.SeriesPosition <= 0) {
+		return false, domain.NewValidationError("series_position", "must be a positive number")
+	}
+	if !hasSeries && book.SeriesPosition != nil {
+		return false, domain.NewValidationError("series_position", "requires a series")
+	}
+
 	return true, nil
 }
 
-func (s *bookService) handleCategory(book *domain.Book) (*domain.Book, *bool, error) {
-	trueValue := true
-	falseValue := false
-	isCategoryCreated := &falseValue
-	catService := NewCategoryService(s.categoryRepo)
+// checkISBNAvailable fails with a conflict when another
+// book (other than bookID) already has the ISBN
+func (s *bookService) checkISBNAvailable(ctx context.Context, repos *repository.Repositories, value *string, bookID string) error {
+	if value == nil {
+		return nil
+	}
+
+	bookOnDB, err := repos.Books.FindByISBN(ctx, *value)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error in book_services while trying to find the book by ISBN: %w", err)
+	}
+
+	if bookOnDB.ID != bookID {
+		return fmt.Errorf("%w: a book with the ISBN %s already exists", domain.ErrConflict, *value)
+	}
+
+	return nil
+}
+
+// handleCategory replaces the categories of the book by the records on the
+// database, they are looked up by ID when it is given and by name otherwise.
+// When create is true the categories not found by name are created,
+// otherwise they are left out
+func (s *bookService) handleCategory(ctx context.Context, repos *repository.Repositories, book *domain.Book, changes *BookChanges, create bool) error {
+	catService := NewCategoryService(repos.Categories, repos.UnitOfWork())
+
+	resolved := make([]domain.Category, 0, len(book.Categories))
+	seen := make(map[string]bool, len(book.Categories))
 
 	for _, category := range book.Categories {
-		// Check if the category already exists
-		// If not, create it
-		_, err := catService.FindCategoryByName(category.Name)
-		if err != nil {
-			if !errors.Is(err, gorm.ErrRecordNotFound) {
-				return nil, nil, fmt.Errorf("error in book_services while trying to find the category by name: %v", err)
+		var categoryOnDB *domain.Category
+		var err error
+
+		if category.ID != "" {
+			categoryOnDB, err = catService.FindCategoryByID(ctx, category.ID)
+			if errors.Is(err, domain.ErrNotFound) {
+				return domain.NewValidationError("categories", fmt.Sprintf("category %q does not exist", category.ID))
+			}
+			if err != nil {
+				return fmt.Errorf("error in book_services while trying to find the category by ID: %w", err)
 			}
+		} else {
+			categoryOnDB, err = catService.FindCategoryByName(ctx, category.Name)
+			if err != nil && !errors.Is(err, domain.ErrNotFound) {
+				return fmt.Errorf("error in book_services while trying to find the category by name: %w", err)
+			}
+		}
 
-			if err := catService.CreateCategory(&category); err != nil {
-				return nil, nil, fmt.Errorf("error in book_services while trying to create the category: %v", err)
+		if seen[category.ID] || (categoryOnDB != nil && seen[categoryOnDB.ID]) {
+			// The same category was sent more than once
+			continue
+		}
+
+		if categoryOnDB == nil && !create {
+			continue
+		}
+
+		if categoryOnDB == nil {
+			newCategory := domain.Category{Name: category.Name}
+			if err := catService.CreateCategory(ctx, &newCategory); err != nil {
+				return fmt.Errorf("error in book_services while trying to create the category: %w", err)
 			}
 
-			isCategoryCreated = &trueValue
+			categoryOnDB = &newCategory
+			changes.CreatedCategories = append(changes.CreatedCategories, newCategory)
+		} else {
+			changes.ReusedCategories = append(changes.ReusedCategories, *categoryOnDB)
 		}
+
+		seen[categoryOnDB.ID] = true
+		resolved = append(resolved, *categoryOnDB)
 	}
 
-	return book, isCategoryCreated, nil
+	book.Categories = resolved
+
+	return nil
 }
 
-func (s *bookService) handleAuthor(book *domain.Book) (*domain.Book, *bool, error) {
-	trueValue := true
-	falseValue := false
-	isAuthorCreated := &falseValue
-	authorService := NewAuthorService(s.authorRepo)
+// handleAuthor replaces the authors of the book by the records on the
+// database, they are looked up by ID when it is given and by name otherwise.
+// When create is true the authors not found by name are created,
+// otherwise they are left out. The credits of the book are filled too,
+// the authors sent by ID keep their current credits on the book
+func (s *bookService) handleAuthor(ctx context.Context, repos *repository.Repositories, book *domain.Book, current []domain.BookAuthor, changes *BookChanges, create bool) error {
+	authorService := NewAuthorService(repos.Authors, repos.UnitOfWork())
+
+	currentCredits := make(map[string]*string, len(current))
+	for _, credit := range current {
+		currentCredits[credit.AuthorID] = credit.CreditedAs
+	}
+
+	resolved := make([]domain.Author, 0, len(book.Authors))
+	credits := make([]domain.BookAuthor, 0, len(book.Authors))
+	seen := make(map[string]bool, len(book.Authors))
 
 	for _, author := range book.Authors {
-		// Check if the author already exists
-		// If not, create it
-		_, err := authorService.FindAuthorByName(author.Name)
-		if err != nil {
-			if !errors.Is(err, gorm.ErrRecordNotFound) {
-				return nil, nil, fmt.Errorf("error in book_services while trying to find the author by name: %v", err)
+		var authorOnDB *domain.Author
+		var err error
+
+		if author.ID != "" {
+			authorOnDB, err = authorService.FindAuthorByID(ctx, author.ID)
+			if errors.Is(err, domain.ErrNotFound) {
+				return domain.NewValidationError("authors", fmt.Sprintf("author %q does not exist", author.ID))
+			}
+			if err != nil {
+				return fmt.Errorf("error in book_services while trying to find the author by ID: %w", err)
+			}
+		} else {
+			authorOnDB, err = authorService.FindAuthorByName(ctx, author.Name)
+			if err != nil && !errors.Is(err, domain.ErrNotFound) {
+				return fmt.Errorf("error in book_services while trying to find the author by name: %w", err)
 			}
+		}
+
+		if seen[author.ID] || (authorOnDB != nil && seen[authorOnDB.ID]) {
+			// The same author was sent more than once
+			continue
+		}
 
-			if err := authorService.CreateAuthor(&author); err != nil {
-				return nil, nil, fmt.Errorf("error in book_services while trying to create the author: %v", err)
+		if authorOnDB == nil && !create {
+			continue
+		}
+
+		if authorOnDB == nil {
+			newAuthor := domain.Author{Name: author.Name}
+			if err := authorService.CreateAuthor(ctx, &newAuthor); err != nil {
+				return fmt.Errorf("error in book_services while trying to create the author: %w", err)
+			}
+
+			authorOnDB = &newAuthor
+			changes.CreatedAuthors = append(changes.CreatedAuthors, newAuthor)
+		} else {
+			changes.ReusedAuthors = append(changes.ReusedAuthors, *authorOnDB)
+		}
+
+		// An author sent by ID keeps the name it is already credited under,
+		// while an author found through one of its aliases is credited
+		// under it, as the alias is written and not as the name was sent
+		credit := domain.BookAuthor{AuthorID: authorOnDB.ID}
+		if author.ID != "" {
+			credit.CreditedAs = currentCredits[authorOnDB.ID]
+		} else if domain.NormalizeName(author.Name) != authorOnDB.Name {
+			alias, err := repos.Authors.FindAliasByName(ctx, author.Name)
+			if err != nil && !errors.Is(err, domain.ErrNotFound) {
+				return fmt.Errorf("error in book_services while trying to find the author alias: %w", err)
 			}
+			if alias != nil && alias.AuthorID == authorOnDB.ID {
+				credit.CreditedAs = &alias.Name
+			}
+		}
+
+		seen[authorOnDB.ID] = true
+		resolved = append(resolved, *authorOnDB)
+		credits = append(credits, credit)
+	}
+
+	book.Authors = resolved
+	book.AuthorCredits = credits
+
+	return nil
+}
+
+// handlePublisher replaces the publisher of the book by the record on the
+// database, it is looked up by ID when it is given and by name otherwise.
+// The publisher not found by name is created
+func (s *bookService) handlePublisher(ctx context.Context, repos *repository.Repositories, book *domain.Book, changes *BookChanges) error {
+	if book.Publisher == nil && book.PublisherID != nil {
+		book.Publisher = &domain.Publisher{}
+		book.Publisher.ID = *book.PublisherID
+	}
+
+	if book.Publisher == nil {
+		book.PublisherID = nil
+		return nil
+	}
+
+	publisherService := NewPublisherService(repos.Publishers, repos.UnitOfWork())
+	publisher := book.Publisher
+
+	var publisherOnDB *domain.Publisher
+	var err error
+
+	if publisher.ID != "" {
+		publisherOnDB, err = publisherService.FindPublisherByID(ctx, publisher.ID)
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.NewValidationError("publisher", fmt.Sprintf("publisher %q does not exist", publisher.ID))
+		}
+		if err != nil {
+			return fmt.Errorf("error in book_services while trying to find the publisher by ID: %w", err)
+		}
+	} else {
+		publisherOnDB, err = publisherService.FindPublisherByName(ctx, publisher.Name)
+		if err != nil && !errors.Is(err, domain.ErrNotFound) {
+			return fmt.Errorf("error in book_services while trying to find the publisher by name: %w", err)
+		}
+	}
+
+	if publisherOnDB == nil {
+		newPublisher := domain.Publisher{Name: publisher.Name}
+		if err := publisherService.CreatePublisher(ctx, &newPublisher); err != nil {
+			return fmt.Errorf("error in book_services while trying to create the publisher: %w", err)
+		}
+
+		publisherOnDB = &newPublisher
+		changes.CreatedPublisher = publisherOnDB
+	} else {
+		changes.ReusedPublisher = publisherOnDB
+	}
+
+	book.Publisher = publisherOnDB
+	book.PublisherID = &publisherOnDB.ID
+
+	return nil
+}
+
+// handleSeries replaces the series of the book by the record on the
+// database, it is looked up by ID when it is given and by name otherwise.
+// The series not found by name is created
+func (s *bookService) handleSeries(ctx context.Context, repos *repository.Repositories, book *domain.Book, changes *BookChanges) error {
+	if book.Series == nil && book.SeriesID != nil {
+		book.Series = &domain.Series{}
+		book.Series.ID = *book.SeriesID
+	}
+
+	if book.Series == nil {
+		book.SeriesID = nil
+		book.SeriesPosition = nil
+		return nil
+	}
+
+	seriesService := NewSeriesService(repos.Series, repos.UnitOfWork())
+	series := book.Series
+
+	var seriesOnDB *domain.Series
+	var err error
+
+	if series.ID != "" {
+		seriesOnDB, err = seriesService.FindSeriesByID(ctx, series.ID)
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.NewValidationError("series", fmt.Sprintf("series %q does not exist", series.ID))
+		}
+		if err != nil {
+			return fmt.Errorf("error in book_services while trying to find the series by ID: %w", err)
+		}
+	} else {
+		seriesOnDB, err = seriesService.FindSeriesByName(ctx, series.Name)
+		if err != nil && !errors.Is(err, domain.ErrNotFound) {
+			return fmt.Errorf("error in book_services while trying to find the series by name: %w", err)
+		}
+	}
 
-			isAuthorCreated = &trueValue
+	if seriesOnDB == nil {
+		newSeries := domain.Series{Name: series.Name}
+		if err := seriesService.CreateSeries(ctx, &newSeries); err != nil {
+			return fmt.Errorf("error in book_services while trying to create the series: %w", err)
 		}
+
+		seriesOnDB = &newSeries
+		changes.CreatedSeries = seriesOnDB
+	} else {
+		changes.ReusedSeries = seriesOnDB
 	}
 
-	return book, isAuthorCreated, nil
+	book.Series = seriesOnDB
+	book.SeriesID = &seriesOnDB.ID
+
+	return nil
 }
 
-func (s *bookService) FindBookByID(id string) (*domain.Book, error) {
+// checkSeriesPositionAvailable fails with a conflict when another
+// book (other than bookID) is already at the position of the series
+func (s *bookService) checkSeriesPositionAvailable(ctx context.Context, repos *repository.Repositories, seriesID *string, position *int, bookID string) error {
+	if seriesID == nil || position == nil {
+		return nil
+	}
+
+	bookOnDB, err := repos.Books.FindBySeriesPosition(ctx, *seriesID, *position)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error in book_services while trying to find the book by series position: %w", err)
+	}
+
+	if bookOnDB.ID != bookID {
+		return fmt.Errorf("%w: the volume %d of the series already exists", domain.ErrConflict, *position)
+	}
+
+	return nil
+}
+
+func (s *bookService) FindBookByID(ctx context.Context, id string) (*domain.Book, error) {
 	if id == "" {
-		return nil, fmt.Errorf("book ID is required")
+		return nil, domain.NewValidationError("id", "is required")
 	}
 
-	book, err := s.bookRepo.FindByID(id)
+	book, err := s.bookRepo.FindByID(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("error in book_services while trying to find the book by ID: %v", err)
+		return nil, fmt.Errorf("error in book_services while trying to find the book by ID: %w", err)
 	}
 
 	return book, nil
 }
 
-func (s *bookService) FindBookByTitle(title string) (*domain.Book, error) {
+func (s *bookService) FindBookByTitle(ctx context.Context, title string) (*domain.Book, error) {
 	if title == "" {
-		return nil, fmt.Errorf("book title is required")
+		return nil, domain.NewValidationError("title", "is required")
 	}
 
-	book, err := s.bookRepo.FindByTitle(title)
+	book, err := s.bookRepo.FindByTitle(ctx, title)
 	if err != nil {
-		return nil, fmt.Errorf("error in book_services while trying to find the book by title: %v", err)
+		return nil, fmt.Errorf("error in book_services while trying to find the book by title: %w", err)
 	}
 
 	return book, nil
 }
 
-func (s *bookService) FindAllBooks() ([]*domain.Book, error) {
-	return s.bookRepo.FindAll()
+// FindBookByISBN finds the book by its ISBN-10 or ISBN-13
+func (s *bookService) FindBookByISBN(ctx context.Context, value string) (*domain.Book, error) {
+	if value == "" {
+		return nil, domain.NewValidationError("isbn", "is required")
+	}
+
+	normalized, err := isbn.Normalize(value)
+	if err != nil {
+		return nil, domain.NewValidationError("isbn", err.Error())
+	}
+
+	book, err := s.bookRepo.FindByISBN(ctx, normalized)
+	if err != nil {
+		return nil, fmt.Errorf("error in book_services while trying to find the book by ISBN: %w", err)
+	}
+
+	return book, nil
 }
 
-func (s *bookService) UpdateBook(book *domain.Book) error {
-	bookID := book.ID
+// FindSeriesVolumes lists the books of the series ordered by their position
+func (s *bookService) FindSeriesVolumes(ctx context.Context, seriesID string) ([]*domain.Book, error) {
+	if seriesID == "" {
+		return nil, domain.NewValidationError("id", "is required")
+	}
 
-	if bookID == "" {
-		return fmt.Errorf("book ID is required")
+	var books []*domain.Book
+	err := s.uow.Do(ctx, func(repos *repository.Repositories) error {
+		if _, err := repos.Series.FindByID(ctx, seriesID); err != nil {
+			return fmt.Errorf("error in book_services while trying to find the series by ID: %w", err)
+		}
+
+		var err error
+		books, err = repos.Books.FindBySeries(ctx, seriesID)
+		if err != nil {
+			return fmt.Errorf("error in book_services while trying to find the series volumes: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	bookOnDB, err := s.FindBookByID(bookID)
+	return books, nil
+}
+
+// FindVolumeNavigation finds the volumes before and after the book in its
+// series, an empty navigation is returned when the book is not in a series
+func (s *bookService) FindVolumeNavigation(ctx context.Context, book *domain.Book) (*VolumeNavigation, error) {
+	previous, next, err := s.bookRepo.FindVolumeNeighbours(ctx, book)
 	if err != nil {
-		return fmt.Errorf("error in book_services while trying to find the book by ID: %v", err)
+		return nil, fmt.Errorf("error in book_services while trying to find the volume navigation: %w", err)
+	}
+
+	return &VolumeNavigation{Previous: previous, Next: next}, nil
+}
+
+func (s *bookService) FindAllBooks(ctx context.Context, filter repository.BookFilter, page repository.PageRequest) ([]*domain.Book, *repository.PageInfo, error) {
+	if err := s.validateBookFilter(&filter); err != nil {
+		return nil, nil, fmt.Errorf("invalid book filter: %w", err)
 	}
 
-	bookOnDB, isCatCreated, err := s.handleCategory(bookOnDB)
+	return s.bookRepo.FindAll(ctx, filter, page)
+}
+
+// exportBatchSize is the number of books loaded at a time by ExportBooks
+const exportBatchSize = 500
+
+// ExportBooks calls fn with every book matching the filter, in batches
+func (s *bookService) ExportBooks(ctx context.Context, filter repository.BookFilter, fn func(books []*domain.Book) error) error {
+	if err := s.validateBookFilter(&filter); err != nil {
+		return fmt.Errorf("invalid book filter: %w", err)
+	}
+
+	err := s.bookRepo.FindInBatches(ctx, filter, exportBatchSize, fn)
 	if err != nil {
-		return fmt.Errorf("error in book_services while handling category: %v", err)
+		return fmt.Errorf("error in book_services while trying to export the books: %w", err)
+	}
+
+	return nil
+}
+
+func (s *bookService) validateBookFilter(filter *repository.BookFilter) error {
+	switch filter.Match {
+	case "":
+		filter.Match = repository.FilterMatchAll
+	case repository.FilterMatchAll, repository.FilterMatchAny:
+	default:
+		return domain.NewValidationError("match", fmt.Sprintf("must be either %q or %q", repository.FilterMatchAll, repository.FilterMatchAny))
+	}
+
+	if filter.CreatedFrom != nil && filter.CreatedTo != nil && !filter.CreatedFrom.Before(*filter.CreatedTo) {
+		return domain.NewValidationError("created_from", "must be before created_to")
 	}
 
-	bookOnDB, isAutCreated, err := s.handleAuthor(bookOnDB)
+	return nil
+}
+
+func (s *bookService) SearchBooks(ctx context.Context, term string, limit, offset int) ([]*repository.BookSearchResult, error) {
+	term = strings.TrimSpace(term)
+	if term == "" {
+		return nil, domain.NewValidationError("q", "is required")
+	}
+
+	if limit <= 0 {
+		limit = repository.DefaultPageLimit
+	}
+	if limit > repository.MaxPageLimit {
+		limit = repository.MaxPageLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	results, err := s.bookRepo.Search(ctx, term, limit, offset)
 	if err != nil {
-		return fmt.Errorf("error in book_services while handling author: %v", err)
+		return nil, fmt.Errorf("error in book_services while trying to search books: %w", err)
 	}
 
-	var isTitleChanged, isSynopsisChanged bool
-	if book.Title != bookOnDB.Title {
-		bookOnDB.Title = book.Title
-		isTitleChanged = true
+	return results, nil
+}
+
+func (s *bookService) UpdateBook(ctx context.Context, book *domain.Book, mode repository.AssociationMode) (*BookChanges, error) {
+	bookID := book.ID
+
+	if bookID == "" {
+		return nil, domain.NewValidationError("id", "is required")
 	}
-	if book.Synopsis != bookOnDB.Synopsis {
-		bookOnDB.Synopsis = book.Synopsis
-		isSynopsisChanged = true
+
+	// The updated book must follow the same rules as a new one
+	ok, err := s.validateBook(book)
+	if !ok {
+		return nil, fmt.Errorf("invalid book: %w", err)
 	}
 
-	if !*isCatCreated && !*isAutCreated && !isTitleChanged && !isSynopsisChanged {
-		// If the title, synopsis, category and author
-		// are not changed, there is no need to update the book
-		return nil
+	switch mode {
+	case "":
+		mode = repository.AssociationReplace
+	case repository.AssociationReplace, repository.AssociationAppend, repository.AssociationRemove:
+	default:
+		return nil, domain.NewValidationError("associations", fmt.Sprintf(
+			"must be one of %q, %q or %q",
+			repository.AssociationReplace, repository.AssociationAppend, repository.AssociationRemove,
+		))
 	}
 
-	err = s.bookRepo.Update(bookOnDB)
+	changes := &BookChanges{}
+	err = s.uow.Do(ctx, func(repos *repository.Repositories) error {
+		bookOnDB, err := repos.Books.FindByID(ctx, bookID)
+		if err != nil {
+			return fmt.Errorf("error in book_services while trying to find the book by ID: %w", err)
+		}
+
+		// The version sent by the client (if any) must be the current one
+		if err := checkVersion(book.Version, bookOnDB.Version); err != nil {
+			return err
+		}
+
+		before := bookSnapshot(bookOnDB)
+
+		// A nil list means the client did not send it,
+		// so the current links are kept untouched.
+		// Nothing is created when the records are being removed
+		createMissing := mode != repository.AssociationRemove
+		var isAssociationChanged bool
+
+		if book.Categories != nil {
+			if err := s.handleCategory(ctx, repos, book, changes, createMissing); err != nil {
+				return fmt.Errorf("error in book_services while handling category: %w", err)
+			}
+
+			linked, unlinked := diffAssociation(bookOnDB.Categories, book.Categories, mode, func(category domain.Category) string {
+				return category.ID
+			})
+			if len(linked) > 0 || len(unlinked) > 0 {
+				isAssociationChanged = true
+				if err := repos.Books.UpdateCategories(ctx, bookOnDB, book.Categories, mode); err != nil {
+					return fmt.Errorf("error in book_services while trying to update the book categories: %w", err)
+				}
+			}
+
+			changes.LinkedCategories = linked
+			changes.UnlinkedCategories = unlinked
+		}
+
+		if book.Authors != nil {
+			if err := s.handleAuthor(ctx, repos, book, bookOnDB.AuthorCredits, changes, createMissing); err != nil {
+				return fmt.Errorf("error in book_services while handling author: %w", err)
+			}
+
+			linked, unlinked := diffAssociation(bookOnDB.Authors, book.Authors, mode, func(author domain.Author) string {
+				return author.ID
+			})
+			if len(linked) > 0 || len(unlinked) > 0 {
+				isAssociationChanged = true
+				if err := repos.Books.UpdateAuthors(ctx, bookOnDB, book.Authors, mode); err != nil {
+					return fmt.Errorf("error in book_services while trying to update the book authors: %w", err)
+				}
+			}
+
+			changes.LinkedAuthors = linked
+			changes.UnlinkedAuthors = unlinked
+
+			if mode != repository.AssociationRemove {
+				credits := changedCredits(bookOnDB.AuthorCredits, book.AuthorCredits)
+				if len(credits) > 0 {
+					isAssociationChanged = true
+					if err := repos.Books.UpdateAuthorCredits(ctx, bookID, credits); err != nil {
+						return fmt.Errorf("error in book_services while trying to credit the book authors: %w", err)
+					}
+				}
+			}
+		}
+
+		// Unlike the authors and categories, a missing publisher or series is removed
+		if err := s.handlePublisher(ctx, repos, book, changes); err != nil {
+			return fmt.Errorf("error in book_services while handling publisher: %w", err)
+		}
+
+		if err := s.handleSeries(ctx, repos, book, changes); err != nil {
+			return fmt.Errorf("error in book_services while handling series: %w", err)
+		}
+
+		var isTitleChanged, isSynopsisChanged, isISBNChanged, isPublisherChanged, isSeriesChanged bool
+		if book.Title != bookOnDB.Title {
+			bookOnDB.Title = book.Title
+			isTitleChanged = true
+		}
+		if book.Synopsis != bookOnDB.Synopsis {
+			bookOnDB.Synopsis = book.Synopsis
+			isSynopsisChanged = true
+		}
+		if !sameOptional(book.ISBN, bookOnDB.ISBN) {
+			if err := s.checkISBNAvailable(ctx, repos, book.ISBN, bookID); err != nil {
+				return err
+			}
+
+			bookOnDB.ISBN = book.ISBN
+			isISBNChanged = true
+		}
+		if !sameOptional(book.PublisherID, bookOnDB.PublisherID) {
+			bookOnDB.PublisherID = book.PublisherID
+			bookOnDB.Publisher = book.Publisher
+			isPublisherChanged = true
+		}
+		if !sameOptional(book.SeriesID, bookOnDB.SeriesID) || !sameOptional(book.SeriesPosition, bookOnDB.SeriesPosition) {
+			if err := s.checkSeriesPositionAvailable(ctx, repos, book.SeriesID, book.SeriesPosition, bookID); err != nil {
+				return err
+			}
+
+			bookOnDB.SeriesID = book.SeriesID
+			bookOnDB.SeriesPosition = book.SeriesPosition
+			bookOnDB.Series = book.Series
+			isSeriesChanged = true
+		}
+
+		if !isAssociationChanged && !isTitleChanged && !isSynopsisChanged && !isISBNChanged && !isPublisherChanged && !isSeriesChanged {
+			// If the title, synopsis, ISBN, publisher, series, category and author
+			// are not changed, there is no need to update the book
+			book.Version = bookOnDB.Version
+			return nil
+		}
+
+		// The book is saved even when only the associations changed,
+		// so its version tells the clients it was modified
+		err = repos.Books.Update(ctx, bookOnDB)
+		if err != nil {
+			return fmt.Errorf("error in book_services while trying to update the book: %w", err)
+		}
+
+		book.Version = bookOnDB.Version
+
+		// The associations were changed through the join tables,
+		// so the book is loaded again to know how it ended up
+		bookAfter, err := repos.Books.FindByID(ctx, bookID)
+		if err != nil {
+			return fmt.Errorf("error in book_services while trying to find the updated book: %w", err)
+		}
+
+		return recordAudit(ctx, repos, auditEntityBook, bookID, domain.AuditActionUpdate, before, bookSnapshot(bookAfter))
+	})
 	if err != nil {
-		return fmt.Errorf("error in book_services while trying to update the book: %v", err)
+		return nil, err
 	}
 
-	return nil
+	return changes, nil
+}
+
+func sameOptional[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
+
+// changedCredits returns the requested credits that differ from the current
+// ones, an author without a current credit is credited under its name
+func changedCredits(current, requested []domain.BookAuthor) []domain.BookAuthor {
+	currentCredits := make(map[string]*string, len(current))
+	for _, credit := range current {
+		currentCredits[credit.AuthorID] = credit.CreditedAs
+	}
+
+	var changed []domain.BookAuthor
+	for _, credit := range requested {
+		if !sameOptional(credit.CreditedAs, currentCredits[credit.AuthorID]) {
+			changed = append(changed, credit)
+		}
+	}
+
+	return changed
+}
+
+// diffAssociation returns the records that the given mode
+// links to and unlinks from a book currently linked to current
+func diffAssociation[T any](current, requested []T, mode repository.AssociationMode, idOf func(T) string) (linked, unlinked []T) {
+	currentIDs := make(map[string]bool, len(current))
+	for _, item := range current {
+		currentIDs[idOf(item)] = true
+	}
+
+	requestedIDs := make(map[string]bool, len(requested))
+	for _, item := range requested {
+		requestedIDs[idOf(item)] = true
+	}
+
+	switch mode {
+	case repository.AssociationRemove:
+		for _, item := range requested {
+			if currentIDs[idOf(item)] {
+				unlinked = append(unlinked, item)
+			}
+		}
+	case repository.AssociationReplace:
+		for _, item := range current {
+			if !requestedIDs[idOf(item)] {
+				unlinked = append(unlinked, item)
+			}
+		}
+		fallthrough
+	case repository.AssociationAppend:
+		for _, item := range requested {
+			if !currentIDs[idOf(item)] {
+				linked = append(linked, item)
+			}
+		}
+	}
+
+	return linked, unlinked
 }
 
-func (s *bookService) DeleteBookByID(id string) error {
+// DeleteBookByID deletes the book, when version is not zero the book
+// is only deleted if it was not changed since that version
+func (s *bookService) DeleteBookByID(ctx context.Context, id string, version int64) error {
 	if id == "" {
-		return fmt.Errorf("book ID is required")
+		return domain.NewValidationError("id", "is required")
 	}
 
-	err := s.bookRepo.Delete(id)
-	if err != nil {
-		return fmt.Errorf("error in book_services while trying to delete the book by ID: %v", err)
+	return s.uow.Do(ctx, func(repos *repository.Repositories) error {
+		bookOnDB, err := repos.Books.FindByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("error in book_services while trying to find the book by ID: %w", err)
+		}
+
+		if err := repos.Books.Delete(ctx, id, version); err != nil {
+			return fmt.Errorf("error in book_services while trying to delete the book by ID: %w", err)
+		}
+
+		return recordAudit(ctx, repos, auditEntityBook, id, domain.AuditActionDelete, bookSnapshot(bookOnDB), nil)
+	})
+}
+
+func (s *bookService) FindDeletedBooks(ctx context.Context, page repository.PageRequest) ([]*domain.Book, *repository.PageInfo, error) {
+	return s.bookRepo.FindDeleted(ctx, page)
+}
+
+// RestoreBookByID brings back a soft deleted book
+func (s *bookService) RestoreBookByID(ctx context.Context, id string) error {
+	if id == "" {
+		return domain.NewValidationError("id", "is required")
 	}
 
-	return nil
+	return s.uow.Do(ctx, func(repos *repository.Repositories) error {
+		if err := repos.Books.Restore(ctx, id); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, repos, auditEntityBook, id, domain.AuditActionRestore, nil, nil)
+	})
+}
+
+// PurgeBookByID permanently deletes a soft deleted book
+func (s *bookService) PurgeBookByID(ctx context.Context, id string) error {
+	if id == "" {
+		return domain.NewValidationError("id", "is required")
+	}
+
+	return s.uow.Do(ctx, func(repos *repository.Repositories) error {
+		released, err := repos.Books.Purge(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := auditReleased(ctx, repos, released); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, repos, auditEntityBook, id, domain.AuditActionPurge, nil, nil)
+	})
 }
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/domain"
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/repository"
+)
+
+// fakeUnitOfWork runs the work on the same repositories, without a transaction
+type fakeUnitOfWork struct {
+	repos *repository.Repositories
+}
+
+func (u fakeUnitOfWork) Do(ctx context.Context, fn func(repos *repository.Repositories) error) error {
+	return fn(u.repos)
+}
+
+// fakeBookRepository always finds the book built by newBook and keeps the
+// credits written, the methods not used by the tests are left unimplemented
+type fakeBookRepository struct {
+	repository.BookRepository
+	newBook func() *domain.Book
+	credits [][]domain.BookAuthor
+}
+
+func (r *fakeBookRepository) FindByID(ctx context.Context, id string) (*domain.Book, error) {
+	return r.newBook(), nil
+}
+
+func (r *fakeBookRepository) Update(ctx context.Context, book *domain.Book) error {
+	book.Version++
+	return nil
+}
+
+func (r *fakeBookRepository) UpdateAuthorCredits(ctx context.Context, bookID string, credits []domain.BookAuthor) error {
+	r.credits = append(r.credits, credits)
+	return nil
+}
+
+type fakeAuthorRepository struct {
+	repository.AuthorRepository
+	authors map[string]*domain.Author
+}
+
+func (r *fakeAuthorRepository) FindByID(ctx context.Context, id string) (*domain.Author, error) {
+	author, ok := r.authors[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+
+	return author, nil
+}
+
+type fakeAuditLogRepository struct {
+	repository.AuditLogRepository
+	logs []*domain.AuditLog
+}
+
+func (r *fakeAuditLogRepository) Create(ctx context.Context, auditLog *domain.AuditLog) error {
+	r.logs = append(r.logs, auditLog)
+	return nil
+}
+
+func TestUpdateBookKeepsAuthorCredits(t *testing.T) {
+	alias := "Alberto Caeiro"
+	author := &domain.Author{Name: "Fernando Pessoa"}
+	author.ID = "author-id"
+
+	newBook := func() *domain.Book {
+		book := &domain.Book{
+			Title:         "O Guardador de Rebanhos",
+			Synopsis:      "Poemas",
+			Authors:       []domain.Author{*author},
+			AuthorCredits: []domain.BookAuthor{{BookID: "book-id", AuthorID: author.ID, CreditedAs: &alias}},
+		}
+		book.ID = "book-id"
+		book.Version = 1
+		return book
+	}
+
+	tests := []struct {
+		name    string
+		authors []domain.Author
+	}{
+		// PATCH leaves out the lists that the patch does not touch
+		{name: "patch of an unrelated field", authors: nil},
+		// PUT (and a patch of the list) sends the current authors back by ID
+		{name: "authors sent back by ID", authors: []domain.Author{{Base: domain.Base{ID: author.ID}, Name: author.Name}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			books := &fakeBookRepository{newBook: newBook}
+			auditLogs := &fakeAuditLogRepository{}
+			repos := &repository.Repositories{
+				Books:     books,
+				Authors:   &fakeAuthorRepository{authors: map[string]*domain.Author{author.ID: author}},
+				AuditLogs: auditLogs,
+			}
+
+			book := &domain.Book{Title: "Other title", Synopsis: "Poemas", Authors: tt.authors}
+			book.ID = "book-id"
+
+			if _, err := NewBookService(books, fakeUnitOfWork{repos}).UpdateBook(context.Background(), book, repository.AssociationReplace); err != nil {
+				t.Fatalf("UpdateBook() error = %v", err)
+			}
+
+			if len(books.credits) > 0 {
+				t.Errorf("credits written = %v, want the current credit kept", books.credits)
+			}
+			if len(auditLogs.logs) != 1 {
+				t.Fatalf("audit logs = %d, want 1", len(auditLogs.logs))
+			}
+			if change, ok := auditLogs.logs[0].Changes["author_credits"]; ok {
+				t.Errorf("audit changes author_credits = %+v, want no change", change)
+			}
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"fmt"
 
 	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/domain"
@@ -8,91 +10,389 @@ import (
 )
 
 type CategoryService interface {
-	CreateCategory(category *domain.Category) error
-	FindCategoryByID(id string) (*domain.Category, error)
-	FindCategoryByName(name string) (*domain.Category, error)
-	FindAllCategories() ([]*domain.Category, error)
-	UpdateCategory(category *domain.Category) error
-	DeleteCategoryByID(id string) error
+	CreateCategory(ctx context.Context, category *domain.Category) error
+	FindCategoryByID(ctx context.Context, id string) (*domain.Category, error)
+	FindCategoryByName(ctx context.Context, name string) (*domain.Category, error)
+	FindAllCategories(ctx context.Context, page repository.PageRequest) ([]*domain.Category, *repository.PageInfo, error)
+	FindCategoryPath(ctx context.Context, id string) ([]*domain.Category, error)
+	FindCategoryTree(ctx context.Context) ([]*CategoryNode, error)
+	FindCategorySubtree(ctx context.Context, id string) (*CategoryNode, error)
+	UpdateCategory(ctx context.Context, category *domain.Category) error
+	DeleteCategoryByID(ctx context.Context, id string, version int64) error
+	FindDeletedCategories(ctx context.Context, page repository.PageRequest) ([]*domain.Category, *repository.PageInfo, error)
+	RestoreCategoryByID(ctx context.Context, id string) error
+	PurgeCategoryByID(ctx context.Context, id string) error
+	MergeCategories(ctx context.Context, sourceID, targetID string, version int64) (*domain.Category, error)
+}
+
+// CategoryNode is a category along with the categories right below it
+type CategoryNode struct {
+	Category *domain.Category
+	Children []*CategoryNode
 }
 
 type categoryService struct {
 	categoryRepo repository.CategoryRepository
+	uow          repository.UnitOfWork
 }
 
-func NewCategoryService(categoryRepo repository.CategoryRepository) CategoryService {
-	return &categoryService{categoryRepo}
+// NewCategoryService builds the category service, the writes run inside
+// a unit of work so they are recorded on the audit log atomically
+func NewCategoryService(categoryRepo repository.CategoryRepository, uow repository.UnitOfWork) CategoryService {
+	return &categoryService{categoryRepo, uow}
 }
 
-func (s *categoryService) CreateCategory(category *domain.Category) error {
+func (s *categoryService) CreateCategory(ctx context.Context, category *domain.Category) error {
+	category.Name = domain.NormalizeName(category.Name)
 	categoryName := category.Name
 
 	if categoryName == "" {
-		return fmt.Errorf("category name is required")
+		return domain.NewValidationError("name", "is required")
 	}
 
 	// Check if the category already exists
-	if _, err := s.FindCategoryByName(categoryName); err == nil {
-		return fmt.Errorf("category already exists")
+	_, err := s.FindCategoryByName(ctx, categoryName)
+	if err == nil {
+		return fmt.Errorf("%w: category already exists", domain.ErrConflict)
+	}
+	if !errors.Is(err, domain.ErrNotFound) {
+		return fmt.Errorf("error while trying to find the category by name: %w", err)
 	}
 
-	return s.categoryRepo.Create(category)
+	return s.uow.Do(ctx, func(repos *repository.Repositories) error {
+		if err := checkCategoryParent(ctx, repos, category); err != nil {
+			return err
+		}
+
+		if err := repos.Categories.Create(ctx, category); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, repos, auditEntityCategory, category.ID, domain.AuditActionCreate, nil, categorySnapshot(category))
+	})
 }
 
-func (s *categoryService) FindCategoryByID(id string) (*domain.Category, error) {
+func (s *categoryService) FindCategoryByID(ctx context.Context, id string) (*domain.Category, error) {
 	if id == "" {
-		return nil, fmt.Errorf("category ID is required")
+		return nil, domain.NewValidationError("id", "is required")
 	}
 
-	return s.categoryRepo.FindByID(id)
+	return s.categoryRepo.FindByID(ctx, id)
 }
 
-func (s *categoryService) FindCategoryByName(name string) (*domain.Category, error) {
+func (s *categoryService) FindCategoryByName(ctx context.Context, name string) (*domain.Category, error) {
+	name = domain.NormalizeName(name)
 	if name == "" {
-		return nil, fmt.Errorf("category name is required")
+		return nil, domain.NewValidationError("name", "is required")
+	}
+
+	return s.categoryRepo.FindByName(ctx, name)
+}
+
+func (s *categoryService) FindAllCategories(ctx context.Context, page repository.PageRequest) ([]*domain.Category, *repository.PageInfo, error) {
+	return s.categoryRepo.FindAll(ctx, page)
+}
+
+// FindCategoryPath returns the categories from the root down to the category,
+// it is the breadcrumb shown along with the category
+func (s *categoryService) FindCategoryPath(ctx context.Context, id string) ([]*domain.Category, error) {
+	if id == "" {
+		return nil, domain.NewValidationError("id", "is required")
 	}
 
-	return s.categoryRepo.FindByName(name)
+	ancestors, err := s.categoryRepo.FindAncestors(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	path := make([]*domain.Category, 0, len(ancestors))
+	for i := len(ancestors) - 1; i >= 0; i-- {
+		path = append(path, ancestors[i])
+	}
+
+	return path, nil
+}
+
+// FindCategoryTree returns the root categories with all of their descendants
+func (s *categoryService) FindCategoryTree(ctx context.Context) ([]*CategoryNode, error) {
+	categories, err := s.categoryRepo.FindTree(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildCategoryTree(categories), nil
 }
 
-func (s *categoryService) FindAllCategories() ([]*domain.Category, error) {
-	return s.categoryRepo.FindAll()
+// FindCategorySubtree returns the category with all of its descendants
+func (s *categoryService) FindCategorySubtree(ctx context.Context, id string) (*CategoryNode, error) {
+	if id == "" {
+		return nil, domain.NewValidationError("id", "is required")
+	}
+
+	// The ID of a merged category resolves to the category it was merged into
+	category, err := s.categoryRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	id = category.ID
+
+	categories, err := s.categoryRepo.FindSubtree(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// The subtree starts with the category itself, so it is the first root.
+	// It is only missing when a cycle was left on parent_id
+	roots := buildCategoryTree(categories)
+	if len(roots) == 0 || roots[0].Category.ID != id {
+		return nil, fmt.Errorf("%w: the category is part of a cycle", domain.ErrInternal)
+	}
+
+	return roots[0], nil
 }
 
-func (s *categoryService) UpdateCategory(category *domain.Category) error {
+func (s *categoryService) UpdateCategory(ctx context.Context, category *domain.Category) error {
 	categoryID := category.ID
-	newCategoryName := category.Name
+	newCategoryName := domain.NormalizeName(category.Name)
 
 	if categoryID == "" {
-		return fmt.Errorf("category ID is required")
+		return domain.NewValidationError("id", "is required")
 	}
 	if newCategoryName == "" {
-		return fmt.Errorf("category name is required")
+		return domain.NewValidationError("name", "is required")
 	}
 
-	categoryOnDB, err := s.FindCategoryByID(categoryID)
+	categoryOnDB, err := s.FindCategoryByID(ctx, categoryID)
 	if err != nil {
-		return fmt.Errorf("error while trying to find the category by ID: %v", err)
+		return fmt.Errorf("error while trying to find the category by ID: %w", err)
 	}
 
+	// The version sent by the client (if any) must be the current one
+	if err := checkVersion(category.Version, categoryOnDB.Version); err != nil {
+		return err
+	}
+
+	before := categorySnapshot(categoryOnDB)
+
 	var isNameChanged bool
 	if newCategoryName != categoryOnDB.Name {
+		// The name may only differ on the case or the accents,
+		// so the one found must be the category itself
+		namesake, err := s.FindCategoryByName(ctx, newCategoryName)
+		if err == nil && namesake.ID != categoryID {
+			return fmt.Errorf("%w: category already exists", domain.ErrConflict)
+		}
+		if err != nil && !errors.Is(err, domain.ErrNotFound) {
+			return fmt.Errorf("error while trying to find the category by name: %w", err)
+		}
+
 		categoryOnDB.Name = newCategoryName
 		isNameChanged = true
 	}
 
-	if !isNameChanged {
-		// If the name is not changed, there is no need to update the category
+	var isParentChanged bool
+	if !sameOptional(category.ParentID, categoryOnDB.ParentID) {
+		categoryOnDB.ParentID = category.ParentID
+		isParentChanged = true
+	}
+
+	if !isNameChanged && !isParentChanged {
+		// If nothing is changed, there is no need to update the category
+		category.Version = categoryOnDB.Version
 		return nil
 	}
 
-	return s.categoryRepo.Update(categoryOnDB)
+	err = s.uow.Do(ctx, func(repos *repository.Repositories) error {
+		if isParentChanged {
+			if err := checkCategoryParent(ctx, repos, categoryOnDB); err != nil {
+				return err
+			}
+		}
+
+		if err := repos.Categories.Update(ctx, categoryOnDB); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, repos, auditEntityCategory, categoryOnDB.ID, domain.AuditActionUpdate, before, categorySnapshot(categoryOnDB))
+	})
+	if err != nil {
+		return err
+	}
+
+	category.Version = categoryOnDB.Version
+
+	return nil
 }
 
-func (s *categoryService) DeleteCategoryByID(id string) error {
+// DeleteCategoryByID deletes the category, when version is not zero the category
+// is only deleted if it was not changed since that version
+func (s *categoryService) DeleteCategoryByID(ctx context.Context, id string, version int64) error {
 	if id == "" {
-		return fmt.Errorf("category ID is required")
+		return domain.NewValidationError("id", "is required")
+	}
+
+	return s.uow.Do(ctx, func(repos *repository.Repositories) error {
+		// The ID of a merged category resolves to the category it was merged into,
+		// which is the one deleted, the same way it is the one returned by the lookups
+		categoryOnDB, err := repos.Categories.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := repos.Categories.Delete(ctx, categoryOnDB.ID, version); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, repos, auditEntityCategory, categoryOnDB.ID, domain.AuditActionDelete, categorySnapshot(categoryOnDB), nil)
+	})
+}
+
+func (s *categoryService) FindDeletedCategories(ctx context.Context, page repository.PageRequest) ([]*domain.Category, *repository.PageInfo, error) {
+	return s.categoryRepo.FindDeleted(ctx, page)
+}
+
+// RestoreCategoryByID brings back a soft deleted category
+func (s *categoryService) RestoreCategoryByID(ctx context.Context, id string) error {
+	if id == "" {
+		return domain.NewValidationError("id", "is required")
+	}
+
+	return s.uow.Do(ctx, func(repos *repository.Repositories) error {
+		if err := repos.Categories.Restore(ctx, id); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, repos, auditEntityCategory, id, domain.AuditActionRestore, nil, nil)
+	})
+}
+
+// PurgeCategoryByID permanently deletes a soft deleted category
+func (s *categoryService) PurgeCategoryByID(ctx context.Context, id string) error {
+	if id == "" {
+		return domain.NewValidationError("id", "is required")
+	}
+
+	return s.uow.Do(ctx, func(repos *repository.Repositories) error {
+		released, err := repos.Categories.Purge(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := auditReleased(ctx, repos, released); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, repos, auditEntityCategory, id, domain.AuditActionPurge, nil, nil)
+	})
+}
+
+// MergeCategories merges the source category into the target one, the books
+// and subcategories of the source move to the target and the source is deleted.
+// When version is not zero the source is only merged if it still has that version
+func (s *categoryService) MergeCategories(ctx context.Context, sourceID, targetID string, version int64) (*domain.Category, error) {
+	if sourceID == "" {
+		return nil, domain.NewValidationError("id", "is required")
+	}
+	if targetID == "" {
+		return nil, domain.NewValidationError("target_id", "is required")
+	}
+
+	var target *domain.Category
+	err := s.uow.Do(ctx, func(repos *repository.Repositories) error {
+		source, err := repos.Categories.FindByID(ctx, sourceID)
+		if err != nil {
+			return err
+		}
+		if source.ID != sourceID {
+			return fmt.Errorf("%w: the category was already merged into another one", domain.ErrConflict)
+		}
+
+		target, err = repos.Categories.FindByID(ctx, targetID)
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.NewValidationError("target_id", "does not match any category")
+		}
+		if err != nil {
+			return err
+		}
+		if target.ID == source.ID {
+			return domain.NewValidationError("target_id", "must be another category")
+		}
+
+		// The subcategories of the source are moved to the target,
+		// which would create a cycle if the target is one of them
+		ancestors, err := repos.Categories.FindAncestors(ctx, target.ID)
+		if err != nil {
+			return fmt.Errorf("error while trying to find the ancestors of the target category: %w", err)
+		}
+		for _, ancestor := range ancestors {
+			if ancestor.ID == source.ID {
+				return domain.NewValidationError("target_id", "cannot be a descendant of the category")
+			}
+		}
+
+		if err := repos.Categories.Merge(ctx, source.ID, target.ID, version); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, repos, auditEntityCategory, source.ID, domain.AuditActionMerge, categorySnapshot(source), mergedSnapshot(target.ID))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return target, nil
+}
+
+// checkCategoryParent makes sure the parent of the category exists and that
+// the category is not one of the ancestors of its parent, which would create a cycle
+func checkCategoryParent(ctx context.Context, repos *repository.Repositories, category *domain.Category) error {
+	if category.ParentID == nil {
+		return nil
+	}
+
+	if category.ID != "" && *category.ParentID == category.ID {
+		return domain.NewValidationError("parent_id", "cannot be the category itself")
+	}
+
+	ancestors, err := repos.Categories.FindAncestors(ctx, *category.ParentID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.NewValidationError("parent_id", "does not match any category")
+	}
+	if err != nil {
+		return fmt.Errorf("error while trying to find the ancestors of the parent category: %w", err)
+	}
+
+	for _, ancestor := range ancestors {
+		if category.ID != "" && ancestor.ID == category.ID {
+			return domain.NewValidationError("parent_id", "cannot be a descendant of the category")
+		}
+	}
+
+	return nil
+}
+
+// buildCategoryTree links the categories to their parents. A category whose
+// parent is not on the list (the parent was soft deleted or the list is a
+// subtree) is returned as a root, the order of the list is kept
+func buildCategoryTree(categories []*domain.Category) []*CategoryNode {
+	nodes := make(map[string]*CategoryNode, len(categories))
+	for _, category := range categories {
+		nodes[category.ID] = &CategoryNode{Category: category}
+	}
+
+	var roots []*CategoryNode
+	for _, category := range categories {
+		node := nodes[category.ID]
+
+		if category.ParentID != nil {
+			if parent, ok := nodes[*category.ParentID]; ok {
+				parent.Children = append(parent.Children, node)
+				continue
+			}
+		}
+
+		roots = append(roots, node)
 	}
 
-	return s.categoryRepo.Delete(id)
+	return roots
 }
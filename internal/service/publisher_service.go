@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/domain"
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/repository"
+)
+
+type PublisherService interface {
+	CreatePublisher(ctx context.Context, publisher *domain.Publisher) error
+	FindPublisherByID(ctx context.Context, id string) (*domain.Publisher, error)
+	FindPublisherByName(ctx context.Context, name string) (*domain.Publisher, error)
+	FindAllPublishers(ctx context.Context, page repository.PageRequest) ([]*domain.Publisher, *repository.PageInfo, error)
+	UpdatePublisher(ctx context.Context, publisher *domain.Publisher) error
+	DeletePublisherByID(ctx context.Context, id string, version int64) error
+	FindDeletedPublishers(ctx context.Context, page repository.PageRequest) ([]*domain.Publisher, *repository.PageInfo, error)
+	RestorePublisherByID(ctx context.Context, id string) error
+	PurgePublisherByID(ctx context.Context, id string) error
+}
+
+type publisherService struct {
+	publisherRepo repository.PublisherRepository
+	uow           repository.UnitOfWork
+}
+
+// NewPublisherService builds the publisher service, the writes run inside
+// a unit of work so they are recorded on the audit log atomically
+func NewPublisherService(publisherRepo repository.PublisherRepository, uow repository.UnitOfWork) PublisherService {
+	return &publisherService{publisherRepo, uow}
+}
+
+func (s *publisherService) CreatePublisher(ctx context.Context, publisher *domain.Publisher) error {
+	publisher.Name = domain.NormalizeName(publisher.Name)
+	publisherName := publisher.Name
+
+	if publisherName == "" {
+		return domain.NewValidationError("name", "is required")
+	}
+
+	// Check if the publisher already exists
+	_, err := s.FindPublisherByName(ctx, publisherName)
+	if err == nil {
+		return fmt.Errorf("%w: publisher already exists", domain.ErrConflict)
+	}
+	if !errors.Is(err, domain.ErrNotFound) {
+		return fmt.Errorf("error while trying to find the publisher by name: %w", err)
+	}
+
+	return s.uow.Do(ctx, func(repos *repository.Repositories) error {
+		if err := repos.Publishers.Create(ctx, publisher); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, repos, auditEntityPublisher, publisher.ID, domain.AuditActionCreate, nil, publisherSnapshot(publisher))
+	})
+}
+
+func (s *publisherService) FindPublisherByID(ctx context.Context, id string) (*domain.Publisher, error) {
+	if id == "" {
+		return nil, domain.NewValidationError("id", "is required")
+	}
+
+	return s.publisherRepo.FindByID(ctx, id)
+}
+
+func (s *publisherService) FindPublisherByName(ctx context.Context, name string) (*domain.Publisher, error) {
+	name = domain.NormalizeName(name)
+	if name == "" {
+		return nil, domain.NewValidationError("name", "is required")
+	}
+
+	return s.publisherRepo.FindByName(ctx, name)
+}
+
+func (s *publisherService) FindAllPublishers(ctx context.Context, page repository.PageRequest) ([]*domain.Publisher, *repository.PageInfo, error) {
+	return s.publisherRepo.FindAll(ctx, page)
+}
+
+func (s *publisherService) UpdatePublisher(ctx context.Context, publisher *domain.Publisher) error {
+	publisherID := publisher.ID
+	newPublisherName := domain.NormalizeName(publisher.Name)
+
+	if publisherID == "" {
+		return domain.NewValidationError("id", "is required")
+	}
+	if newPublisherName == "" {
+		return domain.NewValidationError("name", "is required")
+	}
+
+	publisherOnDB, err := s.FindPublisherByID(ctx, publisherID)
+	if err != nil {
+		return fmt.Errorf("error while trying to find the publisher by ID: %w", err)
+	}
+
+	// The version sent by the client (if any) must be the current one
+	if err := checkVersion(publisher.Version, publisherOnDB.Version); err != nil {
+		return err
+	}
+
+	before := publisherSnapshot(publisherOnDB)
+
+	var isNameChanged bool
+	if newPublisherName != publisherOnDB.Name {
+		// The name may only differ on the case or the accents,
+		// so the one found must be the publisher itself
+		namesake, err := s.FindPublisherByName(ctx, newPublisherName)
+		if err == nil && namesake.ID != publisherID {
+			return fmt.Errorf("%w: publisher already exists", domain.ErrConflict)
+		}
+		if err != nil && !errors.Is(err, domain.ErrNotFound) {
+			return fmt.Errorf("error while trying to find the publisher by name: %w", err)
+		}
+
+		publisherOnDB.Name = newPublisherName
+		isNameChanged = true
+	}
+
+	if !isNameChanged {
+		// If the name is not changed, there is no need to update the publisher
+		publisher.Version = publisherOnDB.Version
+		return nil
+	}
+
+	err = s.uow.Do(ctx, func(repos *repository.Repositories) error {
+		if err := repos.Publishers.Update(ctx, publisherOnDB); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, repos, auditEntityPublisher, publisherOnDB.ID, domain.AuditActionUpdate, before, publisherSnapshot(publisherOnDB))
+	})
+	if err != nil {
+		return err
+	}
+
+	publisher.Version = publisherOnDB.Version
+
+	return nil
+}
+
+// DeletePublisherByID deletes the publisher, when version is not zero the publisher
+// is only deleted if it was not changed since that version
+func (s *publisherService) DeletePublisherByID(ctx context.Context, id string, version int64) error {
+	if id == "" {
+		return domain.NewValidationError("id", "is required")
+	}
+
+	return s.uow.Do(ctx, func(repos *repository.Repositories) error {
+		publisherOnDB, err := repos.Publishers.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := repos.Publishers.Delete(ctx, id, version); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, repos, auditEntityPublisher, id, domain.AuditActionDelete, publisherSnapshot(publisherOnDB), nil)
+	})
+}
+
+func (s *publisherService) FindDeletedPublishers(ctx context.Context, page repository.PageRequest) ([]*domain.Publisher, *repository.PageInfo, error) {
+	return s.publisherRepo.FindDeleted(ctx, page)
+}
+
+// RestorePublisherByID brings back a soft deleted publisher
+func (s *publisherService) RestorePublisherByID(ctx context.Context, id string) error {
+	if id == "" {
+		return domain.NewValidationError("id", "is required")
+	}
+
+	return s.uow.Do(ctx, func(repos *repository.Repositories) error {
+		if err := repos.Publishers.Restore(ctx, id); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, repos, auditEntityPublisher, id, domain.AuditActionRestore, nil, nil)
+	})
+}
+
+// PurgePublisherByID permanently deletes a soft deleted publisher
+func (s *publisherService) PurgePublisherByID(ctx context.Context, id string) error {
+	if id == "" {
+		return domain.NewValidationError("id", "is required")
+	}
+
+	return s.uow.Do(ctx, func(repos *repository.Repositories) error {
+		released, err := repos.Publishers.Purge(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := auditReleased(ctx, repos, released); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, repos, auditEntityPublisher, id, domain.AuditActionPurge, nil, nil)
+	})
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/domain"
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/repository"
+)
+
+// PurgeActor is the actor recorded on the audit log for the expired records purge
+const PurgeActor = "purger"
+
+// PurgeReport counts the records permanently deleted by entity
+type PurgeReport struct {
+	Books      int64
+	Authors    int64
+	Categories int64
+	Publishers int64
+	Series     int64
+}
+
+type PurgeService interface {
+	// PurgeExpired permanently deletes the records
+	// soft deleted for longer than the retention period
+	PurgeExpired(ctx context.Context) (*PurgeReport, error)
+}
+
+type purgeService struct {
+	repos     *repository.Repositories
+	retention time.Duration
+	batchSize int
+}
+
+func NewPurgeService(repos *repository.Repositories, retention time.Duration, batchSize int) PurgeService {
+	return &purgeService{repos, retention, batchSize}
+}
+
+func (s *purgeService) PurgeExpired(ctx context.Context) (*PurgeReport, error) {
+	cutoff := time.Now().Add(-s.retention)
+	report := &PurgeReport{}
+	ctx = WithActor(ctx, PurgeActor)
+
+	// The books go first so the join table rows are
+	// cleaned before the authors, categories, publishers and series
+	var err error
+	report.Books, err = s.repos.Books.PurgeDeletedBefore(ctx, cutoff, s.batchSize, auditPurged(ctx, auditEntityBook))
+	if err != nil {
+		return report, fmt.Errorf("error in purge_services while purging books: %w", err)
+	}
+
+	report.Authors, err = s.repos.Authors.PurgeDeletedBefore(ctx, cutoff, s.batchSize, auditPurged(ctx, auditEntityAuthor))
+	if err != nil {
+		return report, fmt.Errorf("error in purge_services while purging authors: %w", err)
+	}
+
+	report.Categories, err = s.repos.Categories.PurgeDeletedBefore(ctx, cutoff, s.batchSize, auditPurged(ctx, auditEntityCategory))
+	if err != nil {
+		return report, fmt.Errorf("error in purge_services while purging categories: %w", err)
+	}
+
+	report.Publishers, err = s.repos.Publishers.PurgeDeletedBefore(ctx, cutoff, s.batchSize, auditPurged(ctx, auditEntityPublisher))
+	if err != nil {
+		return report, fmt.Errorf("error in purge_services while purging publishers: %w", err)
+	}
+
+	report.Series, err = s.repos.Series.PurgeDeletedBefore(ctx, cutoff, s.batchSize, auditPurged(ctx, auditEntitySeries))
+	if err != nil {
+		return report, fmt.Errorf("error in purge_services while purging series: %w", err)
+	}
+
+	return report, nil
+}
+
+// auditPurged records every purged record and every row released by the
+// purge on the audit log, in the same transaction as the batch that purged them
+func auditPurged(ctx context.Context, entityType string) repository.PurgedFunc {
+	return func(repos *repository.Repositories, ids []string, released []repository.ReleasedReference) error {
+		if err := auditReleased(ctx, repos, released); err != nil {
+			return err
+		}
+
+		for _, id := range ids {
+			if err := recordAudit(ctx, repos, entityType, id, domain.AuditActionPurge, nil, nil); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/domain"
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/repository"
+)
+
+type SeriesService interface {
+	CreateSeries(ctx context.Context, series *domain.Series) error
+	FindSeriesByID(ctx context.Context, id string) (*domain.Series, error)
+	FindSeriesByName(ctx context.Context, name string) (*domain.Series, error)
+	FindAllSeries(ctx context.Context, page repository.PageRequest) ([]*domain.Series, *repository.PageInfo, error)
+	UpdateSeries(ctx context.Context, series *domain.Series) error
+	DeleteSeriesByID(ctx context.Context, id string, version int64) error
+	FindDeletedSeries(ctx context.Context, page repository.PageRequest) ([]*domain.Series, *repository.PageInfo, error)
+	RestoreSeriesByID(ctx context.Context, id string) error
+	PurgeSeriesByID(ctx context.Context, id string) error
+}
+
+type seriesService struct {
+	seriesRepo repository.SeriesRepository
+	uow        repository.UnitOfWork
+}
+
+// NewSeriesService builds the series service, the writes run inside
+// a unit of work so they are recorded on the audit log atomically
+func NewSeriesService(seriesRepo repository.SeriesRepository, uow repository.UnitOfWork) SeriesService {
+	return &seriesService{seriesRepo, uow}
+}
+
+func (s *seriesService) CreateSeries(ctx context.Context, series *domain.Series) error {
+	series.Name = domain.NormalizeName(series.Name)
+	seriesName := series.Name
+
+	if seriesName == "" {
+		return domain.NewValidationError("name", "is required")
+	}
+
+	// Check if the series already exists
+	_, err := s.FindSeriesByName(ctx, seriesName)
+	if err == nil {
+		return fmt.Errorf("%w: series already exists", domain.ErrConflict)
+	}
+	if !errors.Is(err, domain.ErrNotFound) {
+		return fmt.Errorf("error while trying to find the series by name: %w", err)
+	}
+
+	return s.uow.Do(ctx, func(repos *repository.Repositories) error {
+		if err := repos.Series.Create(ctx, series); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, repos, auditEntitySeries, series.ID, domain.AuditActionCreate, nil, seriesSnapshot(series))
+	})
+}
+
+func (s *seriesService) FindSeriesByID(ctx context.Context, id string) (*domain.Series, error) {
+	if id == "" {
+		return nil, domain.NewValidationError("id", "is required")
+	}
+
+	return s.seriesRepo.FindByID(ctx, id)
+}
+
+func (s *seriesService) FindSeriesByName(ctx context.Context, name string) (*domain.Series, error) {
+	name = domain.NormalizeName(name)
+	if name == "" {
+		return nil, domain.NewValidationError("name", "is required")
+	}
+
+	return s.seriesRepo.FindByName(ctx, name)
+}
+
+func (s *seriesService) FindAllSeries(ctx context.Context, page repository.PageRequest) ([]*domain.Series, *repository.PageInfo, error) {
+	return s.seriesRepo.FindAll(ctx, page)
+}
+
+func (s *seriesService) UpdateSeries(ctx context.Context, series *domain.Series) error {
+	seriesID := series.ID
+	newSeriesName := domain.NormalizeName(series.Name)
+
+	if seriesID == "" {
+		return domain.NewValidationError("id", "is required")
+	}
+	if newSeriesName == "" {
+		return domain.NewValidationError("name", "is required")
+	}
+
+	seriesOnDB, err := s.FindSeriesByID(ctx, seriesID)
+	if err != nil {
+		return fmt.Errorf("error while trying to find the series by ID: %w", err)
+	}
+
+	// The version sent by the client (if any) must be the current one
+	if err := checkVersion(series.Version, seriesOnDB.Version); err != nil {
+		return err
+	}
+
+	before := seriesSnapshot(seriesOnDB)
+
+	var isNameChanged bool
+	if newSeriesName != seriesOnDB.Name {
+		// The name may only differ on the case or the accents,
+		// so the one found must be the series itself
+		namesake, err := s.FindSeriesByName(ctx, newSeriesName)
+		if err == nil && namesake.ID != seriesID {
+			return fmt.Errorf("%w: series already exists", domain.ErrConflict)
+		}
+		if err != nil && !errors.Is(err, domain.ErrNotFound) {
+			return fmt.Errorf("error while trying to find the series by name: %w", err)
+		}
+
+		seriesOnDB.Name = newSeriesName
+		isNameChanged = true
+	}
+
+	if !isNameChanged {
+		// If the name is not changed, there is no need to update the series
+		series.Version = seriesOnDB.Version
+		return nil
+	}
+
+	err = s.uow.Do(ctx, func(repos *repository.Repositories) error {
+		if err := repos.Series.Update(ctx, seriesOnDB); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, repos, auditEntitySeries, seriesOnDB.ID, domain.AuditActionUpdate, before, seriesSnapshot(seriesOnDB))
+	})
+	if err != nil {
+		return err
+	}
+
+	series.Version = seriesOnDB.Version
+
+	return nil
+}
+
+// DeleteSeriesByID deletes the series, when version is not zero the series
+// is only deleted if it was not changed since that version
+func (s *seriesService) DeleteSeriesByID(ctx context.Context, id string, version int64) error {
+	if id == "" {
+		return domain.NewValidationError("id", "is required")
+	}
+
+	return s.uow.Do(ctx, func(repos *repository.Repositories) error {
+		seriesOnDB, err := repos.Series.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := repos.Series.Delete(ctx, id, version); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, repos, auditEntitySeries, id, domain.AuditActionDelete, seriesSnapshot(seriesOnDB), nil)
+	})
+}
+
+func (s *seriesService) FindDeletedSeries(ctx context.Context, page repository.PageRequest) ([]*domain.Series, *repository.PageInfo, error) {
+	return s.seriesRepo.FindDeleted(ctx, page)
+}
+
+// RestoreSeriesByID brings back a soft deleted series
+func (s *seriesService) RestoreSeriesByID(ctx context.Context, id string) error {
+	if id == "" {
+		return domain.NewValidationError("id", "is required")
+	}
+
+	return s.uow.Do(ctx, func(repos *repository.Repositories) error {
+		if err := repos.Series.Restore(ctx, id); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, repos, auditEntitySeries, id, domain.AuditActionRestore, nil, nil)
+	})
+}
+
+// PurgeSeriesByID permanently deletes a soft deleted series
+func (s *seriesService) PurgeSeriesByID(ctx context.Context, id string) error {
+	if id == "" {
+		return domain.NewValidationError("id", "is required")
+	}
+
+	return s.uow.Do(ctx, func(repos *repository.Repositories) error {
+		released, err := repos.Series.Purge(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := auditReleased(ctx, repos, released); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, repos, auditEntitySeries, id, domain.AuditActionPurge, nil, nil)
+	})
+}
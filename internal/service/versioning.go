@@ -0,0 +1,18 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/domain"
+)
+
+// checkVersion fails when the client based its change on another version
+// of the record than the current one. A zero expected version skips the check
+func checkVersion(expected, current int64) error {
+	if expected != 0 && expected != current {
+		return fmt.Errorf("%w: expected version %d but the current version is %d",
+			domain.ErrPreconditionFailed, expected, current)
+	}
+
+	return nil
+}
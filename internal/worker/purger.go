@@ -0,0 +1,52 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/felipe-lima-coelho/desafio-taghos-backend-jr/internal/service"
+)
+
+// Purger periodically removes the soft deleted records
+// past the retention period configured on the purge service
+type Purger struct {
+	purgeService service.PurgeService
+	interval     time.Duration
+}
+
+func NewPurger(purgeService service.PurgeService, interval time.Duration) *Purger {
+	return &Purger{purgeService, interval}
+}
+
+// Run purges once right away and then on every interval,
+// it blocks until the context is cancelled
+func (p *Purger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		p.purge(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *Purger) purge(ctx context.Context) {
+	report, err := p.purgeService.PurgeExpired(ctx)
+	if err != nil {
+		log.Printf("Error while purging the deleted records: %v", err)
+	}
+	if report == nil {
+		return
+	}
+
+	log.Printf(
+		"Purged deleted records: %d books, %d authors, %d categories, %d publishers, %d series",
+		report.Books, report.Authors, report.Categories, report.Publishers, report.Series,
+	)
+}
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_books_search_vector;
+ALTER TABLE books DROP COLUMN IF EXISTS search_vector;
+DROP TEXT SEARCH CONFIGURATION IF EXISTS portuguese_unaccent;
\ No newline at end of file
@@ -0,0 +1,22 @@
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+-- Portuguese stemming after removing the accents, so "ação" matches "acao"
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_ts_config WHERE cfgname = 'portuguese_unaccent') THEN
+        CREATE TEXT SEARCH CONFIGURATION portuguese_unaccent (COPY = portuguese);
+        ALTER TEXT SEARCH CONFIGURATION portuguese_unaccent
+            ALTER MAPPING FOR hword, hword_part, word
+            WITH unaccent, portuguese_stem;
+    END IF;
+END
+$$;
+
+ALTER TABLE books
+    ADD COLUMN IF NOT EXISTS search_vector TSVECTOR
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('portuguese_unaccent', coalesce(title, '')), 'A') ||
+        setweight(to_tsvector('portuguese_unaccent', coalesce(synopsis, '')), 'B')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_books_search_vector ON books USING GIN (search_vector);
\ No newline at end of file
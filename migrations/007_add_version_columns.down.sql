@@ -0,0 +1,3 @@
+ALTER TABLE books DROP COLUMN IF EXISTS version;
+ALTER TABLE authors DROP COLUMN IF EXISTS version;
+ALTER TABLE categories DROP COLUMN IF EXISTS version;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE authors ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE books ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
\ No newline at end of file
@@ -0,0 +1,17 @@
+-- actor is verified by the API (the administrator token or an internal job), while
+-- claimed_actor is the unauthenticated X-Actor header sent by the client
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id CHAR(36) PRIMARY KEY,
+    entity_type VARCHAR(50) NOT NULL,
+    entity_id CHAR(36) NOT NULL,
+    action VARCHAR(20) NOT NULL,
+    actor VARCHAR(100) NOT NULL,
+    claimed_actor VARCHAR(100) DEFAULT NULL,
+    changes JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_logs_entity ON audit_logs (entity_type, entity_id);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_actor ON audit_logs (actor);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_claimed_actor ON audit_logs (claimed_actor);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_created_at ON audit_logs (created_at);
\ No newline at end of file
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_books_isbn;
+ALTER TABLE books DROP COLUMN IF EXISTS isbn;
\ No newline at end of file
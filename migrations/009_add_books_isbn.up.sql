@@ -0,0 +1,4 @@
+ALTER TABLE books ADD COLUMN IF NOT EXISTS isbn VARCHAR(13) DEFAULT NULL;
+
+-- Only one live book per ISBN, the deleted ones do not count
+CREATE UNIQUE INDEX IF NOT EXISTS idx_books_isbn ON books (isbn) WHERE deleted_at IS NULL;
\ No newline at end of file
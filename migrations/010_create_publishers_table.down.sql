@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_books_publisher_id;
+ALTER TABLE books DROP COLUMN IF EXISTS publisher_id;
+DROP INDEX IF EXISTS idx_publishers_name;
+DROP TABLE IF EXISTS publishers;
\ No newline at end of file
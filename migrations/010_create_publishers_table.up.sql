@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS publishers (
+    id CHAR(36) PRIMARY KEY,
+    name VARCHAR(150) NOT NULL,
+    version BIGINT NOT NULL DEFAULT 1,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    deleted_at TIMESTAMP WITH TIME ZONE DEFAULT NULL
+);
+
+-- Only one live publisher per name, the deleted ones do not count
+CREATE UNIQUE INDEX IF NOT EXISTS idx_publishers_name ON publishers (name) WHERE deleted_at IS NULL;
+
+-- Purging a publisher keeps its books, they are left without a publisher
+ALTER TABLE books ADD COLUMN IF NOT EXISTS publisher_id CHAR(36) DEFAULT NULL
+    CONSTRAINT fk_publisher
+        REFERENCES publishers (id)
+        ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_books_publisher_id ON books (publisher_id);
\ No newline at end of file
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_books_series_position;
+ALTER TABLE books DROP CONSTRAINT IF EXISTS chk_books_series_position;
+ALTER TABLE books DROP COLUMN IF EXISTS series_position;
+ALTER TABLE books DROP COLUMN IF EXISTS series_id;
+DROP INDEX IF EXISTS idx_series_name;
+DROP TABLE IF EXISTS series;
\ No newline at end of file
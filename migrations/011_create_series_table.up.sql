@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS series (
+    id CHAR(36) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    version BIGINT NOT NULL DEFAULT 1,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    deleted_at TIMESTAMP WITH TIME ZONE DEFAULT NULL
+);
+
+-- Only one live series per name, the deleted ones do not count
+CREATE UNIQUE INDEX IF NOT EXISTS idx_series_name ON series (name) WHERE deleted_at IS NULL;
+
+ALTER TABLE books ADD COLUMN IF NOT EXISTS series_id CHAR(36) DEFAULT NULL
+    CONSTRAINT fk_series
+        REFERENCES series (id);
+ALTER TABLE books ADD COLUMN IF NOT EXISTS series_position INTEGER DEFAULT NULL;
+
+-- A book is either out of any series or has a positive position in one
+ALTER TABLE books ADD CONSTRAINT chk_books_series_position
+    CHECK ((series_id IS NULL AND series_position IS NULL) OR (series_id IS NOT NULL AND series_position > 0));
+
+-- Each position of a series belongs to a single live book
+CREATE UNIQUE INDEX IF NOT EXISTS idx_books_series_position ON books (series_id, series_position) WHERE deleted_at IS NULL;
\ No newline at end of file
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_categories_parent_id;
+ALTER TABLE categories DROP COLUMN IF EXISTS parent_id;
\ No newline at end of file
@@ -0,0 +1,5 @@
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS parent_id CHAR(36) DEFAULT NULL
+    CONSTRAINT fk_parent
+        REFERENCES categories (id);
+
+CREATE INDEX IF NOT EXISTS idx_categories_parent_id ON categories (parent_id);
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE book_authors DROP COLUMN IF EXISTS credited_as;
+DROP TABLE IF EXISTS author_aliases;
\ No newline at end of file
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS author_aliases (
+    id CHAR(36) PRIMARY KEY,
+    author_id CHAR(36) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT fk_author
+        FOREIGN KEY (author_id)
+        REFERENCES authors (id)
+        ON DELETE CASCADE
+);
+
+-- An alias must resolve to a single author
+CREATE UNIQUE INDEX IF NOT EXISTS idx_author_aliases_name ON author_aliases (name);
+CREATE INDEX IF NOT EXISTS idx_author_aliases_author_id ON author_aliases (author_id);
+
+-- The alias the author is credited under on the book, NULL for the author name
+ALTER TABLE book_authors ADD COLUMN IF NOT EXISTS credited_as VARCHAR(100) DEFAULT NULL;
\ No newline at end of file
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS category_redirects;
+DROP TABLE IF EXISTS author_redirects;
\ No newline at end of file
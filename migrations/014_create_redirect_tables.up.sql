@@ -0,0 +1,25 @@
+-- A merged record keeps resolving to the record it was merged into,
+-- old_id has no foreign key so the redirect outlives the purge of the merged record
+CREATE TABLE IF NOT EXISTS author_redirects (
+    old_id CHAR(36) PRIMARY KEY,
+    author_id CHAR(36) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT fk_author
+        FOREIGN KEY (author_id)
+        REFERENCES authors (id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_author_redirects_author_id ON author_redirects (author_id);
+
+CREATE TABLE IF NOT EXISTS category_redirects (
+    old_id CHAR(36) PRIMARY KEY,
+    category_id CHAR(36) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT fk_category
+        FOREIGN KEY (category_id)
+        REFERENCES categories (id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_category_redirects_category_id ON category_redirects (category_id);
\ No newline at end of file
@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS idx_author_aliases_name_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_author_aliases_name ON author_aliases (name);
+
+DROP INDEX IF EXISTS idx_categories_name_key;
+DROP INDEX IF EXISTS idx_authors_name_key;
+
+ALTER TABLE author_aliases DROP COLUMN IF EXISTS name_key;
+ALTER TABLE categories DROP COLUMN IF EXISTS name_key;
+ALTER TABLE authors DROP COLUMN IF EXISTS name_key;
+
+DROP FUNCTION IF EXISTS normalize_name(TEXT);
+DROP FUNCTION IF EXISTS immutable_unaccent(TEXT);
\ No newline at end of file
@@ -0,0 +1,29 @@
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+-- unaccent is only STABLE because its dictionary can change, fixing the
+-- dictionary lets it be used on the generated columns and their indexes
+CREATE OR REPLACE FUNCTION immutable_unaccent(value TEXT) RETURNS TEXT AS $$
+    SELECT public.unaccent('public.unaccent'::REGDICTIONARY, value)
+$$ LANGUAGE SQL IMMUTABLE PARALLEL SAFE STRICT;
+
+-- The key the names are compared by: trimmed, single spaced, lower case and without accents
+CREATE OR REPLACE FUNCTION normalize_name(value TEXT) RETURNS TEXT AS $$
+    SELECT lower(public.immutable_unaccent(regexp_replace(btrim(value), '\s+', ' ', 'g')))
+$$ LANGUAGE SQL IMMUTABLE PARALLEL SAFE STRICT;
+
+-- The names already stored are trimmed and single spaced, as the new ones
+UPDATE authors SET name = regexp_replace(btrim(name), '\s+', ' ', 'g');
+UPDATE categories SET name = regexp_replace(btrim(name), '\s+', ' ', 'g');
+UPDATE author_aliases SET name = regexp_replace(btrim(name), '\s+', ' ', 'g');
+
+ALTER TABLE authors ADD COLUMN IF NOT EXISTS name_key TEXT GENERATED ALWAYS AS (normalize_name(name)) STORED;
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS name_key TEXT GENERATED ALWAYS AS (normalize_name(name)) STORED;
+ALTER TABLE author_aliases ADD COLUMN IF NOT EXISTS name_key TEXT GENERATED ALWAYS AS (normalize_name(name)) STORED;
+
+-- Only one live author or category per key, the deleted (and merged) ones do not count.
+-- The live near-duplicates must be merged before running this migration
+CREATE UNIQUE INDEX IF NOT EXISTS idx_authors_name_key ON authors (name_key) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_name_key ON categories (name_key) WHERE deleted_at IS NULL;
+
+DROP INDEX IF EXISTS idx_author_aliases_name;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_author_aliases_name_key ON author_aliases (name_key);
\ No newline at end of file
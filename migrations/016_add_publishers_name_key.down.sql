@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_publishers_name_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_publishers_name ON publishers (name) WHERE deleted_at IS NULL;
+
+ALTER TABLE publishers DROP COLUMN IF EXISTS name_key;
\ No newline at end of file
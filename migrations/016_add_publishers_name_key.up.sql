@@ -0,0 +1,9 @@
+-- The names already stored are trimmed and single spaced, as the new ones
+UPDATE publishers SET name = regexp_replace(btrim(name), '\s+', ' ', 'g');
+
+ALTER TABLE publishers ADD COLUMN IF NOT EXISTS name_key TEXT GENERATED ALWAYS AS (normalize_name(name)) STORED;
+
+-- Only one live publisher per key, the deleted ones do not count.
+-- The live near-duplicates must be renamed or deleted before running this migration
+DROP INDEX IF EXISTS idx_publishers_name;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_publishers_name_key ON publishers (name_key) WHERE deleted_at IS NULL;
\ No newline at end of file
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_series_name_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_series_name ON series (name) WHERE deleted_at IS NULL;
+
+ALTER TABLE series DROP COLUMN IF EXISTS name_key;
\ No newline at end of file
@@ -0,0 +1,9 @@
+-- The names already stored are trimmed and single spaced, as the new ones
+UPDATE series SET name = regexp_replace(btrim(name), '\s+', ' ', 'g');
+
+ALTER TABLE series ADD COLUMN IF NOT EXISTS name_key TEXT GENERATED ALWAYS AS (normalize_name(name)) STORED;
+
+-- Only one live series per key, the deleted ones do not count.
+-- The live near-duplicates must be renamed or deleted before running this migration
+DROP INDEX IF EXISTS idx_series_name;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_series_name_key ON series (name_key) WHERE deleted_at IS NULL;
\ No newline at end of file